@@ -0,0 +1,513 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+const debuggerHelp = `Commands:
+  step [n], s [n]        apply the next n events (default 1)
+  continue, c            apply events until a breakpoint matches
+  goto <index>           move to the given event index, replaying if needed
+  back [n], b [n]        move back n events (default 1) by replaying
+  break <cond>...        break when all conditions match, conditions are
+                           event=<type> node=<id> msg=<type> seqno=<n> epoch=<n>
+                           status=epoch-changed|epoch-state-changed|
+                                  watermarks-changed|checkpoint-stable
+  breakpoints            list breakpoints
+  delete <id>            delete a breakpoint
+  event                  print the current event
+  actions                print the actions produced by the current event
+  status [node]          print the status of the current event's node, or of node
+  help                   print this help
+  quit, q                exit the debugger
+`
+
+// statusPredicates compare a node's facets before and after applying an event.
+var statusPredicates = map[string]func(before, after *status.Facets) bool{
+	"epoch-changed": func(before, after *status.Facets) bool {
+		return before.EpochNumber != after.EpochNumber
+	},
+	"epoch-state-changed": func(before, after *status.Facets) bool {
+		return before.EpochNumber != after.EpochNumber || before.EpochState != after.EpochState
+	},
+	"watermarks-changed": func(before, after *status.Facets) bool {
+		return before.LowWatermark != after.LowWatermark || before.HighWatermark != after.HighWatermark
+	},
+	"checkpoint-stable": func(before, after *status.Facets) bool {
+		return before.StableCheckpoint != after.StableCheckpoint
+	},
+}
+
+// breakpoint stops a continue once every one of its set conditions matches.
+type breakpoint struct {
+	id        int
+	text      string
+	eventType string
+	msgType   string
+	nodeID    *uint64
+	seqNo     *uint64
+	epoch     *uint64
+	predicate func(before, after *status.Facets) bool
+}
+
+func parseBreakpoint(id int, conditions []string) (*breakpoint, error) {
+	if len(conditions) == 0 {
+		return nil, errors.Errorf("breakpoint requires at least one condition")
+	}
+
+	bp := &breakpoint{
+		id:   id,
+		text: strings.Join(conditions, " "),
+	}
+
+	parseUint := func(key, value string) (*uint64, error) {
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid %s %q", key, value)
+		}
+		return &n, nil
+	}
+
+	for _, condition := range conditions {
+		parts := strings.SplitN(condition, "=", 2)
+		if len(parts) != 2 {
+			return nil, errors.Errorf("condition %q is not of the form key=value", condition)
+		}
+		key, value := parts[0], parts[1]
+
+		var err error
+		switch key {
+		case "event":
+			bp.eventType = value
+		case "msg":
+			bp.msgType = value
+		case "node":
+			bp.nodeID, err = parseUint(key, value)
+		case "seqno":
+			bp.seqNo, err = parseUint(key, value)
+		case "epoch":
+			bp.epoch, err = parseUint(key, value)
+		case "status":
+			predicate, ok := statusPredicates[value]
+			if !ok {
+				return nil, errors.Errorf("unknown status predicate %q", value)
+			}
+			bp.predicate = predicate
+		default:
+			return nil, errors.Errorf("unknown condition %q", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bp, nil
+}
+
+func (bp *breakpoint) matches(event *recording.Event, before, after *status.Facets) bool {
+	if bp.nodeID != nil && *bp.nodeID != event.NodeId {
+		return false
+	}
+
+	if bp.eventType != "" && bp.eventType != eventTypeName(event.StateEvent) {
+		return false
+	}
+
+	if bp.msgType != "" {
+		step, ok := event.StateEvent.Type.(*state.Event_Step)
+		if !ok || bp.msgType != msgTypeName(step.Step.Msg) {
+			return false
+		}
+	}
+
+	if bp.seqNo != nil {
+		seqNo, ok := findUint64(event.StateEvent.ProtoReflect(), isSeqNoField)
+		if !ok || seqNo != *bp.seqNo {
+			return false
+		}
+	}
+
+	if bp.epoch != nil {
+		epoch, ok := findUint64(event.StateEvent.ProtoReflect(), isEpochField)
+		if !ok || epoch != *bp.epoch {
+			return false
+		}
+	}
+
+	if bp.predicate != nil && !bp.predicate(before, after) {
+		return false
+	}
+
+	return true
+}
+
+func isSeqNoField(fd pref.FieldDescriptor) bool {
+	return fd.Name() == "seq_no"
+}
+
+func isEpochField(fd pref.FieldDescriptor) bool {
+	switch fd.Name() {
+	case "epoch", "new_epoch", "epoch_number":
+		return true
+	case "number":
+		return fd.ContainingMessage().Name() == "EpochConfig"
+	default:
+		return false
+	}
+}
+
+// findUint64 returns the value of the shallowest populated uint64 field of
+// the message matching the given predicate, ignoring repeated fields.
+func findUint64(m pref.Message, match func(pref.FieldDescriptor) bool) (uint64, bool) {
+	queue := []pref.Message{m}
+	for len(queue) > 0 {
+		m, queue = queue[0], queue[1:]
+
+		var (
+			result uint64
+			found  bool
+		)
+		m.Range(func(fd pref.FieldDescriptor, v pref.Value) bool {
+			switch {
+			case fd.IsList() || fd.IsMap():
+			case fd.Kind() == pref.Uint64Kind && match(fd):
+				result, found = v.Uint(), true
+				return false
+			case fd.Kind() == pref.MessageKind:
+				queue = append(queue, v.Message())
+			}
+			return true
+		})
+		if found {
+			return result, true
+		}
+	}
+
+	return 0, false
+}
+
+// debugger steps through a recording on demand.  As recordings may only be
+// read forwards, moving backwards replays the recording from its start.
+type debugger struct {
+	input       io.ReadSeeker
+	output      io.Writer
+	logLevel    statemachine.LogLevel
+	verboseText bool
+
+	replay *replay
+	eof    bool
+
+	// facets holds the facets of each node after its latest event, which
+	// breakpoints compare with those after its next.
+	facets map[uint64]*status.Facets
+
+	breakpoints    []*breakpoint
+	nextBreakpoint int
+}
+
+func newDebugger(input io.ReadSeeker, output io.Writer, logLevel statemachine.LogLevel, verboseText bool) (*debugger, error) {
+	d := &debugger{
+		input:          input,
+		output:         output,
+		logLevel:       logLevel,
+		verboseText:    verboseText,
+		nextBreakpoint: 1,
+	}
+
+	if err := d.rewind(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *debugger) rewind() error {
+	if _, err := d.input.Seek(0, io.SeekStart); err != nil {
+		return errors.WithMessage(err, "could not rewind input")
+	}
+
+	r, err := newReplay(d.input, d.output, d.logLevel)
+	if err != nil {
+		return err
+	}
+
+	d.replay = r
+	d.eof = false
+	d.facets = map[uint64]*status.Facets{}
+
+	return nil
+}
+
+// next applies the next event of the recording, returning whether any
+// breakpoint matched it.  At the end of the recording, d.eof is set.
+func (d *debugger) next() (*breakpoint, error) {
+	if d.replay.err != nil {
+		return nil, errors.WithMessage(d.replay.err, "cannot advance past failed event, go back to continue")
+	}
+
+	if !d.replay.next() {
+		if d.replay.err != nil {
+			return nil, d.replay.err
+		}
+		d.eof = true
+		return nil, nil
+	}
+
+	event := d.replay.event
+	before, ok := d.facets[event.NodeId]
+	if _, initialize := event.StateEvent.Type.(*state.Event_Initialize); !ok || initialize {
+		before = &status.Facets{}
+	}
+	after := d.replay.facets()
+	d.facets[event.NodeId] = after
+
+	for _, bp := range d.breakpoints {
+		if bp.matches(event, before, after) {
+			return bp, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (d *debugger) printEvent() error {
+	if d.replay.event == nil {
+		fmt.Fprintf(d.output, "no event applied yet\n")
+		return nil
+	}
+
+	text, err := textFormat(withoutActions(d.replay.event), !d.verboseText)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal event")
+	}
+	fmt.Fprintf(d.output, "% 6d %s\n", d.replay.index, text)
+	return nil
+}
+
+func (d *debugger) printActions() error {
+	if d.replay.actions == nil {
+		fmt.Fprintf(d.output, "no event applied yet\n")
+		return nil
+	}
+
+	iter := d.replay.actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		text, err := textFormat(action, !d.verboseText)
+		if err != nil {
+			return errors.WithMessage(err, "could not marshal actions")
+		}
+		fmt.Fprintf(d.output, "       actions: %s\n", text)
+	}
+	return nil
+}
+
+func (d *debugger) printStatus(nodeID uint64) error {
+	node, ok := d.replay.machines.nodes[nodeID]
+	if !ok {
+		fmt.Fprintf(d.output, "node %d is not initialized\n", nodeID)
+		return nil
+	}
+
+	s, err := node.machine.Status()
+	if err != nil {
+		return errors.WithMessage(err, "could not retrieve status")
+	}
+	fmt.Fprint(d.output, s.Pretty())
+	fmt.Fprint(d.output, "\n")
+	return nil
+}
+
+// step applies count events, printing each.
+func (d *debugger) step(count uint64) error {
+	for i := uint64(0); i < count; i++ {
+		if _, err := d.next(); err != nil {
+			return err
+		}
+		if d.eof {
+			fmt.Fprintf(d.output, "end of recording after event %d\n", d.replay.index)
+			return nil
+		}
+		if err := d.printEvent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cont applies events until a breakpoint matches or the recording ends.
+func (d *debugger) cont() error {
+	for {
+		bp, err := d.next()
+		if err != nil {
+			return err
+		}
+		if d.eof {
+			fmt.Fprintf(d.output, "end of recording after event %d\n", d.replay.index)
+			return nil
+		}
+		if bp != nil {
+			fmt.Fprintf(d.output, "breakpoint %d (%s) hit\n", bp.id, bp.text)
+			return d.printEvent()
+		}
+	}
+}
+
+// seek moves to the given index, replaying from the start if it is behind
+// the current one.
+func (d *debugger) seek(index uint64) error {
+	if index < d.replay.index {
+		if err := d.rewind(); err != nil {
+			return err
+		}
+	}
+
+	for d.replay.index < index {
+		if _, err := d.next(); err != nil {
+			return err
+		}
+		if d.eof {
+			fmt.Fprintf(d.output, "end of recording after event %d\n", d.replay.index)
+			return nil
+		}
+	}
+
+	return d.printEvent()
+}
+
+func parseCount(args []string) (uint64, error) {
+	if len(args) == 0 {
+		return 1, nil
+	}
+	count, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, errors.Errorf("invalid count %q", args[0])
+	}
+	return count, nil
+}
+
+// execute runs a single command, returning true once the debugger should exit.
+func (d *debugger) execute(command string, args []string) (bool, error) {
+	switch command {
+	case "step", "s":
+		count, err := parseCount(args)
+		if err != nil {
+			return false, err
+		}
+		return false, d.step(count)
+	case "continue", "c":
+		return false, d.cont()
+	case "goto":
+		if len(args) != 1 {
+			return false, errors.Errorf("goto requires an index")
+		}
+		index, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return false, errors.Errorf("invalid index %q", args[0])
+		}
+		return false, d.seek(index)
+	case "back", "b":
+		count, err := parseCount(args)
+		if err != nil {
+			return false, err
+		}
+		if count > d.replay.index {
+			count = d.replay.index
+		}
+		return false, d.seek(d.replay.index - count)
+	case "break":
+		bp, err := parseBreakpoint(d.nextBreakpoint, args)
+		if err != nil {
+			return false, err
+		}
+		d.nextBreakpoint++
+		d.breakpoints = append(d.breakpoints, bp)
+		fmt.Fprintf(d.output, "breakpoint %d: %s\n", bp.id, bp.text)
+	case "breakpoints":
+		for _, bp := range d.breakpoints {
+			fmt.Fprintf(d.output, "breakpoint %d: %s\n", bp.id, bp.text)
+		}
+	case "delete":
+		if len(args) != 1 {
+			return false, errors.Errorf("delete requires a breakpoint id")
+		}
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return false, errors.Errorf("invalid breakpoint id %q", args[0])
+		}
+		for i, bp := range d.breakpoints {
+			if bp.id == id {
+				d.breakpoints = append(d.breakpoints[:i], d.breakpoints[i+1:]...)
+				return false, nil
+			}
+		}
+		return false, errors.Errorf("no breakpoint %d", id)
+	case "event":
+		return false, d.printEvent()
+	case "actions":
+		return false, d.printActions()
+	case "status":
+		if len(args) == 0 {
+			if d.replay.event == nil {
+				return false, errors.Errorf("no event applied yet, specify a node")
+			}
+			return false, d.printStatus(d.replay.event.NodeId)
+		}
+		nodeID, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil {
+			return false, errors.Errorf("invalid node %q", args[0])
+		}
+		return false, d.printStatus(nodeID)
+	case "help":
+		fmt.Fprint(d.output, debuggerHelp)
+	case "quit", "q":
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown command %q, try help", command)
+	}
+
+	return false, nil
+}
+
+// run reads commands until quit or the commands are exhausted, reporting
+// any errors and continuing.
+func (d *debugger) run(commands io.Reader) error {
+	scanner := bufio.NewScanner(commands)
+	for {
+		fmt.Fprint(d.output, "(mircat) ")
+		if !scanner.Scan() {
+			fmt.Fprint(d.output, "\n")
+			return scanner.Err()
+		}
+
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		quit, err := d.execute(fields[0], fields[1:])
+		if err != nil {
+			fmt.Fprintf(d.output, "error: %s\n", err)
+		}
+		if quit {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/proto"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// actionDiff is a position in the action list at which the recorded and
+// replayed actions differ.  Either action is nil if the list ended early.
+type actionDiff struct {
+	position int
+	recorded *state.Action
+	replayed *state.Action
+}
+
+// diffActions compares the recorded actions with the replayed ones position
+// by position, returning the positions at which they differ.
+func diffActions(recorded *recording.ActionList, replayed *statemachine.ActionList) []*actionDiff {
+	var replayedList []*state.Action
+	iter := replayed.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		replayedList = append(replayedList, action)
+	}
+
+	length := len(recorded.List)
+	if len(replayedList) > length {
+		length = len(replayedList)
+	}
+
+	var diffs []*actionDiff
+	for i := 0; i < length; i++ {
+		diff := &actionDiff{position: i}
+		if i < len(recorded.List) {
+			diff.recorded = recorded.List[i]
+		}
+		if i < len(replayedList) {
+			diff.replayed = replayedList[i]
+		}
+
+		if diff.recorded != nil && diff.replayed != nil && proto.Equal(diff.recorded, diff.replayed) {
+			continue
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return diffs
+}
+
+// diffFields returns a line per field path at which the messages differ.
+func diffFields(path string, recorded, replayed pref.Message) []string {
+	var result []string
+
+	fields := recorded.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		fieldPath := string(fd.Name())
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		switch {
+		case fd.IsList():
+			recordedList, replayedList := recorded.Get(fd).List(), replayed.Get(fd).List()
+			if recordedList.Len() != replayedList.Len() {
+				result = append(result, fmt.Sprintf("%s: len=%d != len=%d", fieldPath, recordedList.Len(), replayedList.Len()))
+				continue
+			}
+			for j := 0; j < recordedList.Len(); j++ {
+				elementPath := fmt.Sprintf("%s[%d]", fieldPath, j)
+				if fd.Kind() == pref.MessageKind {
+					result = append(result, diffFields(elementPath, recordedList.Get(j).Message(), replayedList.Get(j).Message())...)
+				} else if !scalarEqual(fd, recordedList.Get(j), replayedList.Get(j)) {
+					result = append(result, fmt.Sprintf("%s: %v != %v", elementPath, recordedList.Get(j), replayedList.Get(j)))
+				}
+			}
+		case fd.IsMap():
+			if recorded.Get(fd).Map().Len() != replayed.Get(fd).Map().Len() {
+				result = append(result, fmt.Sprintf("%s: len=%d != len=%d", fieldPath, recorded.Get(fd).Map().Len(), replayed.Get(fd).Map().Len()))
+			}
+		case fd.Kind() == pref.MessageKind:
+			hasRecorded, hasReplayed := recorded.Has(fd), replayed.Has(fd)
+			switch {
+			case !hasRecorded && !hasReplayed:
+			case hasRecorded != hasReplayed:
+				result = append(result, fmt.Sprintf("%s: set=%t != set=%t", fieldPath, hasRecorded, hasReplayed))
+			default:
+				result = append(result, diffFields(fieldPath, recorded.Get(fd).Message(), replayed.Get(fd).Message())...)
+			}
+		case fd.Kind() == pref.BytesKind:
+			if !scalarEqual(fd, recorded.Get(fd), replayed.Get(fd)) {
+				result = append(result, fmt.Sprintf("%s: %x != %x", fieldPath, recorded.Get(fd).Bytes(), replayed.Get(fd).Bytes()))
+			}
+		default:
+			if !scalarEqual(fd, recorded.Get(fd), replayed.Get(fd)) {
+				result = append(result, fmt.Sprintf("%s: %v != %v", fieldPath, recorded.Get(fd), replayed.Get(fd)))
+			}
+		}
+	}
+
+	return result
+}
+
+func scalarEqual(fd pref.FieldDescriptor, a, b pref.Value) bool {
+	if fd.Kind() == pref.BytesKind {
+		return bytes.Equal(a.Bytes(), b.Bytes())
+	}
+	return a.Interface() == b.Interface()
+}
+
+// reportDivergence writes each differing action, and for actions present in
+// both lists, the paths of the fields which differ.
+func reportDivergence(output io.Writer, index uint64, event *recording.Event, diffs []*actionDiff, shortBytes bool) error {
+	fmt.Fprintf(output, "% 6d node %d replayed actions diverge from recording at %d position(s)\n", index, event.NodeId, len(diffs))
+
+	format := func(action *state.Action) (string, error) {
+		if action == nil {
+			return "<none>", nil
+		}
+		return textFormat(action, shortBytes)
+	}
+
+	for _, diff := range diffs {
+		recordedText, err := format(diff.recorded)
+		if err != nil {
+			return err
+		}
+		replayedText, err := format(diff.replayed)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(output, "       action %d:\n", diff.position)
+		fmt.Fprintf(output, "         - recorded: %s\n", recordedText)
+		fmt.Fprintf(output, "         + replayed: %s\n", replayedText)
+
+		if diff.recorded == nil || diff.replayed == nil {
+			continue
+		}
+
+		for _, line := range diffFields("", diff.recorded.ProtoReflect(), diff.replayed.ProtoReflect()) {
+			fmt.Fprintf(output, "           %s\n", line)
+		}
+	}
+
+	return nil
+}
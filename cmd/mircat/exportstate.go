@@ -0,0 +1,326 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"container/list"
+	"crypto"
+	_ "crypto/sha256" // Registers the hashes selectable with --hasher.
+	_ "crypto/sha512"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+// exportHashers are the hashers selectable for export-state, 'none'
+// selects no hasher.
+var exportHashers = map[string]processor.Hasher{
+	"sha256": crypto.SHA256,
+	"sha512": crypto.SHA512,
+}
+
+// exportTarget selects the node and the index in the recording whose
+// persisted state to export.  The hasher verifies the data recovered for
+// each request, when nil, only the data retained by the recording is
+// exported, unverified.
+type exportTarget struct {
+	nodeID uint64
+	index  uint64
+	output string
+	hasher processor.Hasher
+}
+
+// exportedState is what a node had persisted at an index of the recording.
+// The WAL is rebuilt from the entries the node loaded when it last
+// initialized, and the writes and truncations it received since, as the
+// processor performs the actions it receives before reporting back.  The
+// request store holds the requests the node persisted, with the data
+// retained by the recording, less the requests below the client low
+// watermarks of the last stable checkpoint, which the node no longer needs.
+type exportedState struct {
+	hasher   processor.Hasher
+	loaded   []*state.EventLoadPersistedEntry
+	wal      *testengine.WAL
+	requests []*msgs.RequestAck
+	data     map[string][]byte
+}
+
+func collectState(input io.Reader, logOutput io.Writer, logLevel statemachine.LogLevel, target *exportTarget) (*exportedState, error) {
+	r, err := newReplay(input, logOutput, logLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the target node's events are applied, so that the other nodes
+	// cannot fail the export.
+	r.nodeIDs = []uint64{target.nodeID}
+
+	es := &exportedState{
+		hasher: target.hasher,
+		data:   map[string][]byte{},
+	}
+
+	for r.index < target.index && r.next() {
+		if r.event.NodeId != target.nodeID {
+			continue
+		}
+
+		if err := es.observe(r.event, r.actions); err != nil {
+			return nil, errors.WithMessagef(err, "could not follow node %d at index %d", target.nodeID, r.index)
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	if r.index < target.index {
+		return nil, errors.Errorf("recording ends at index %d, before index %d", r.index, target.index)
+	}
+
+	if es.wal == nil {
+		return nil, errors.Errorf("node %d has not completed initialization by index %d", target.nodeID, target.index)
+	}
+
+	es.prune(r.machines.nodes[target.nodeID].machine.Facets().StableCheckpoint)
+
+	return es, nil
+}
+
+// prune drops the requests below the client low watermarks of the stable
+// checkpoint, as recorded by its entry in the WAL.
+func (es *exportedState) prune(stableSeqNo uint64) {
+	var networkState *msgs.NetworkState
+	for el := es.wal.List.Front(); el != nil; el = el.Next() {
+		cEntry, ok := el.Value.(*msgs.Persistent).Type.(*msgs.Persistent_CEntry)
+		if ok && cEntry.CEntry.SeqNo == stableSeqNo {
+			networkState = cEntry.CEntry.NetworkState
+		}
+	}
+	if networkState == nil {
+		return
+	}
+
+	lowWatermarks := map[uint64]uint64{}
+	for _, client := range networkState.Clients {
+		lowWatermarks[client.Id] = client.LowWatermark
+	}
+
+	requests := es.requests[:0]
+	for _, ack := range es.requests {
+		if ack.ReqNo < lowWatermarks[ack.ClientId] {
+			continue
+		}
+		requests = append(requests, ack)
+	}
+	es.requests = requests
+}
+
+func requestKey(ack *msgs.RequestAck) string {
+	return fmt.Sprintf("%d-%d-%x", ack.ClientId, ack.ReqNo, ack.Digest)
+}
+
+func (es *exportedState) observe(event *recording.Event, actions *statemachine.ActionList) error {
+	switch et := event.StateEvent.Type.(type) {
+	case *state.Event_Initialize:
+		es.loaded = nil
+		es.wal = nil
+	case *state.Event_LoadPersistedEntry:
+		es.loaded = append(es.loaded, et.LoadPersistedEntry)
+	case *state.Event_CompleteInitialization:
+		if len(es.loaded) == 0 {
+			return errors.Errorf("completed initialization without loading any entries")
+		}
+		es.wal = &testengine.WAL{
+			LowIndex: es.loaded[0].Index,
+			List:     list.New(),
+		}
+		for _, loaded := range es.loaded {
+			es.wal.List.PushBack(loaded.Entry)
+		}
+		es.loaded = nil
+	case *state.Event_RequestPersisted:
+		ack := et.RequestPersisted.RequestAck
+		key := requestKey(ack)
+		data, ok := es.data[key]
+		if !ok {
+			es.requests = append(es.requests, ack)
+		}
+		if data == nil {
+			es.data[key] = event.RequestData
+		}
+	case *state.Event_ActionsReceived:
+		if es.wal == nil {
+			return errors.Errorf("received actions before completing initialization")
+		}
+		walActions := &statemachine.ActionList{}
+		iter := actions.Iterator()
+		for action := iter.Next(); action != nil; action = iter.Next() {
+			switch action.Type.(type) {
+			case *state.Action_AppendWriteAhead, *state.Action_TruncateWriteAhead:
+				walActions.PushBack(action)
+			}
+		}
+		if _, err := processor.ProcessWALActions(es.wal, walActions); err != nil {
+			return errors.WithMessage(err, "could not process WAL actions")
+		}
+	}
+
+	return nil
+}
+
+// requestData returns the data of a request, if it is reconstructable.  The
+// data is retained by recordings made with eventlog.RetainRequestDataOpt,
+// otherwise only the data of requests proposed by testengine clients may be
+// recovered, when there is a hasher to verify it.
+func (es *exportedState) requestData(ack *msgs.RequestAck) ([]byte, bool) {
+	data := es.data[requestKey(ack)]
+	if es.hasher == nil {
+		return data, data != nil
+	}
+	if data == nil {
+		data = testengine.ClientRequestData(ack.ClientId, ack.ReqNo)
+	}
+	h := es.hasher.New()
+	h.Write(data)
+	return data, bytes.Equal(h.Sum(nil), ack.Digest)
+}
+
+// emptyDir creates the directory, unless it exists and is not empty.
+func emptyDir(path string) error {
+	entries, err := ioutil.ReadDir(path)
+	switch {
+	case os.IsNotExist(err):
+		return errors.WithMessagef(os.MkdirAll(path, 0700), "could not create %s", path)
+	case err != nil:
+		return errors.WithMessagef(err, "could not read %s", path)
+	case len(entries) > 0:
+		return errors.Errorf("%s is not empty", path)
+	}
+	return nil
+}
+
+func (es *exportedState) writeWAL(path string) error {
+	if err := emptyDir(path); err != nil {
+		return err
+	}
+
+	wal, err := simplewal.Open(path)
+	if err != nil {
+		return err
+	}
+	defer wal.Close()
+
+	// The log must begin at index 1, so the indices below the low index are
+	// padded with the first entry, then truncated away.
+	first := es.wal.List.Front().Value.(*msgs.Persistent)
+	for i := uint64(1); i < es.wal.LowIndex; i++ {
+		if err := wal.Write(i, first); err != nil {
+			return errors.WithMessagef(err, "could not pad WAL index %d", i)
+		}
+	}
+
+	var writeErr error
+	es.wal.LoadAll(func(index uint64, p *msgs.Persistent) {
+		if writeErr == nil {
+			writeErr = errors.WithMessagef(wal.Write(index, p), "could not write WAL index %d", index)
+		}
+	})
+	if writeErr != nil {
+		return writeErr
+	}
+
+	if es.wal.LowIndex > 1 {
+		if err := wal.Truncate(es.wal.LowIndex); err != nil {
+			return errors.WithMessagef(err, "could not truncate WAL to index %d", es.wal.LowIndex)
+		}
+	}
+
+	if err := wal.Sync(); err != nil {
+		return errors.WithMessage(err, "could not sync WAL")
+	}
+
+	return wal.Close()
+}
+
+// writeReqStore stores the persisted requests, returning the number of
+// those omitted as their data could not be reconstructed.
+func (es *exportedState) writeReqStore(path string) (int, error) {
+	if err := emptyDir(path); err != nil {
+		return 0, err
+	}
+
+	store, err := reqstore.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer store.Close()
+
+	omitted := 0
+	for _, ack := range es.requests {
+		digest := ack.Digest
+		if len(digest) == 0 {
+			// Null requests are allocated with an empty digest, and no data.
+			digest = []byte{}
+		} else {
+			data, ok := es.requestData(ack)
+			if !ok {
+				omitted++
+				continue
+			}
+			if err := store.PutRequest(ack, data); err != nil {
+				return 0, errors.WithMessagef(err, "could not store request client_id=%d req_no=%d", ack.ClientId, ack.ReqNo)
+			}
+		}
+
+		if err := store.PutAllocation(ack.ClientId, ack.ReqNo, digest); err != nil {
+			return 0, errors.WithMessagef(err, "could not allocate request client_id=%d req_no=%d", ack.ClientId, ack.ReqNo)
+		}
+	}
+
+	return omitted, errors.WithMessage(store.Sync(), "could not sync request store")
+}
+
+func (a *arguments) exportState(output io.Writer) error {
+	es, err := collectState(a.input, a.logOutput(output), a.logLevel, a.exportTarget)
+	if err != nil {
+		return err
+	}
+
+	walPath := filepath.Join(a.exportTarget.output, "wal")
+	if err := es.writeWAL(walPath); err != nil {
+		return err
+	}
+
+	reqStorePath := filepath.Join(a.exportTarget.output, "reqstore")
+	omitted, err := es.writeReqStore(reqStorePath)
+	if err != nil {
+		return err
+	}
+
+	highIndex := es.wal.LowIndex + uint64(es.wal.List.Len()) - 1
+	fmt.Fprintf(output, "Wrote WAL entries %d-%d of node %d at index %d to %s\n", es.wal.LowIndex, highIndex, a.exportTarget.nodeID, a.exportTarget.index, walPath)
+	fmt.Fprintf(output, "Wrote %d requests to %s\n", len(es.requests)-omitted, reqStorePath)
+	if omitted > 0 {
+		fmt.Fprintf(output, "WARNING: omitted %d requests whose data the recording does not retain, record with eventlog.RetainRequestDataOpt to export them\n", omitted)
+	}
+
+	return nil
+}
@@ -41,6 +41,7 @@ var (
 		"AddResults",
 		"ActionsReceived",
 		"ClientActionsReceived",
+		"UpdateParameters",
 	}
 
 	allMsgTypes = []string{
@@ -100,6 +101,11 @@ func excludedByNodeID(re *recording.Event, nodeIDs []uint64) bool {
 }
 
 type arguments struct {
+	command       string
+	traceTarget   *traceTarget
+	exportTarget  *exportTarget
+	mergeInputs   []string
+	splitOutput   string
 	input         io.ReadCloser
 	interactive   bool
 	printActions  bool
@@ -111,6 +117,12 @@ type arguments struct {
 	notStepTypes  []string
 	statusIndices []uint64
 	verboseText   bool
+	checkDiverge  bool
+	overrides     *initOverrides
+	simulateSteps int
+	format        string
+	repl          bool
+	commands      io.Reader
 }
 
 type namedLogger struct {
@@ -231,93 +243,168 @@ func (s *stateMachines) status(event *recording.Event) (*status.StateMachine, er
 	return node.machine.Status()
 }
 
-func (a *arguments) shouldPrint(event *recording.Event) bool {
-	var eventTypeText string
-	switch event.StateEvent.Type.(type) {
+// replay iterates over a recording, applying each event to the state machine
+// of its node.
+type replay struct {
+	reader   *eventlog.Reader
+	machines *stateMachines
+
+	// nodeIDs, if set, restricts the events applied to those of the given
+	// nodes.  The events of other nodes are still read, with nil actions.
+	nodeIDs []uint64
+
+	// index is the one-based index of the current event in the recording.
+	index   uint64
+	event   *recording.Event
+	actions *statemachine.ActionList
+
+	// err is set once reading or applying an event fails, as the state
+	// machines may be corrupt, and ends the replay.
+	err error
+}
+
+func newReplay(input io.Reader, logOutput io.Writer, logLevel statemachine.LogLevel) (*replay, error) {
+	reader, err := eventlog.NewReader(input)
+	if err != nil {
+		return nil, errors.WithMessage(err, "bad input file")
+	}
+
+	return &replay{
+		reader:   reader,
+		machines: newStateMachines(logOutput, logLevel),
+	}, nil
+}
+
+// next reads and applies the next event, returning false at the end of the
+// recording or once an error has occurred, which is then reported by err.
+// The current event is only replaced once the next is applied.
+func (r *replay) next() bool {
+	if r.err != nil {
+		return false
+	}
+
+	event, err := r.reader.ReadEvent()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		r.err = errors.WithMessage(err, "failed reading input")
+		return false
+	}
+
+	var actions *statemachine.ActionList
+	if !excludedByNodeID(event, r.nodeIDs) {
+		actions, err = r.machines.apply(event)
+		if err != nil {
+			r.err = err
+			return false
+		}
+	}
+
+	r.index++
+	r.event = event
+	r.actions = actions
+	return true
+}
+
+// facets returns the facets of the current event's node after applying it.
+func (r *replay) facets() *status.Facets {
+	return r.machines.nodes[r.event.NodeId].machine.Facets()
+}
+
+// withoutActions returns the event without any recorded actions, as they are
+// verbose and are reported instead when checking for divergence.
+func withoutActions(event *recording.Event) *recording.Event {
+	if event.Actions == nil {
+		return event
+	}
+
+	return &recording.Event{
+		NodeId:     event.NodeId,
+		Time:       event.Time,
+		StateEvent: event.StateEvent,
+	}
+}
+
+// eventTypeName returns the name used to filter and break on the event's type.
+func eventTypeName(event *state.Event) string {
+	switch event.Type.(type) {
 	case *state.Event_Initialize:
-		eventTypeText = "Initialize"
+		return "Initialize"
 	case *state.Event_LoadPersistedEntry:
-		eventTypeText = "LoadPersistedEntry"
+		return "LoadPersistedEntry"
 	case *state.Event_CompleteInitialization:
-		eventTypeText = "CompleteInitialization"
+		return "CompleteInitialization"
 	case *state.Event_TickElapsed:
-		eventTypeText = "TickElapsed"
+		return "TickElapsed"
 	case *state.Event_HashResult:
-		eventTypeText = "HashResult"
+		return "HashResult"
 	case *state.Event_CheckpointResult:
-		eventTypeText = "CheckpointResult"
+		return "CheckpointResult"
 	case *state.Event_RequestPersisted:
-		eventTypeText = "RequestPersisted"
+		return "RequestPersisted"
 	case *state.Event_ActionsReceived:
-		eventTypeText = "ActionsReceived"
+		return "ActionsReceived"
+	case *state.Event_UpdateParameters:
+		return "UpdateParameters"
 	case *state.Event_Step:
-		eventTypeText = "Step"
+		return "Step"
 	case *state.Event_StateTransferComplete:
-		eventTypeText = "StateTransferComplete"
+		return "StateTransferComplete"
 	case *state.Event_StateTransferFailed:
-		eventTypeText = "StateTransferFailed"
+		return "StateTransferFailed"
+	default:
+		panic(fmt.Sprintf("Unknown event type '%T'", event.Type))
+	}
+}
+
+// msgTypeName returns the name used to filter and break on the message's type.
+func msgTypeName(msg *msgs.Msg) string {
+	switch msg.Type.(type) {
+	case *msgs.Msg_Preprepare:
+		return "Preprepare"
+	case *msgs.Msg_Prepare:
+		return "Prepare"
+	case *msgs.Msg_Commit:
+		return "Commit"
+	case *msgs.Msg_Checkpoint:
+		return "Checkpoint"
+	case *msgs.Msg_Suspect:
+		return "Suspect"
+	case *msgs.Msg_EpochChange:
+		return "EpochChange"
+	case *msgs.Msg_EpochChangeAck:
+		return "EpochChangeAck"
+	case *msgs.Msg_NewEpoch:
+		return "NewEpoch"
+	case *msgs.Msg_NewEpochEcho:
+		return "NewEpochEcho"
+	case *msgs.Msg_NewEpochReady:
+		return "NewEpochReady"
+	case *msgs.Msg_FetchBatch:
+		return "FetchBatch"
+	case *msgs.Msg_ForwardBatch:
+		return "ForwardBatch"
+	case *msgs.Msg_FetchRequest:
+		return "FetchRequest"
+	case *msgs.Msg_ForwardRequest:
+		return "ForwardRequest"
+	case *msgs.Msg_RequestAck:
+		return "RequestAck"
 	default:
-		panic(fmt.Sprintf("Unknown event type '%T'", event.StateEvent.Type))
+		panic("unknown message type")
 	}
+}
 
-	if excludeByType(eventTypeText, a.eventTypes, a.notEventTypes) {
+func (a *arguments) shouldPrint(event *recording.Event) bool {
+	if excludeByType(eventTypeName(event.StateEvent), a.eventTypes, a.notEventTypes) {
 		return false
 	}
 
-	switch et := event.StateEvent.Type.(type) {
-	case *state.Event_Initialize:
-	case *state.Event_LoadPersistedEntry:
-	case *state.Event_CompleteInitialization:
-	case *state.Event_TickElapsed:
-	case *state.Event_HashResult:
-	case *state.Event_CheckpointResult:
-	case *state.Event_RequestPersisted:
-	case *state.Event_ActionsReceived:
-	case *state.Event_Step:
-		var stepTypeText string
-		switch et.Step.Msg.Type.(type) {
-		case *msgs.Msg_Preprepare:
-			stepTypeText = "Preprepare"
-		case *msgs.Msg_Prepare:
-			stepTypeText = "Prepare"
-		case *msgs.Msg_Commit:
-			stepTypeText = "Commit"
-		case *msgs.Msg_Checkpoint:
-			stepTypeText = "Checkpoint"
-		case *msgs.Msg_Suspect:
-			stepTypeText = "Suspect"
-		case *msgs.Msg_EpochChange:
-			stepTypeText = "EpochChange"
-		case *msgs.Msg_EpochChangeAck:
-			stepTypeText = "EpochChangeAck"
-		case *msgs.Msg_NewEpoch:
-			stepTypeText = "NewEpoch"
-		case *msgs.Msg_NewEpochEcho:
-			stepTypeText = "NewEpochEcho"
-		case *msgs.Msg_NewEpochReady:
-			stepTypeText = "NewEpochReady"
-		case *msgs.Msg_FetchBatch:
-			stepTypeText = "FetchBatch"
-		case *msgs.Msg_ForwardBatch:
-			stepTypeText = "ForwardBatch"
-		case *msgs.Msg_FetchRequest:
-			stepTypeText = "FetchRequest"
-		case *msgs.Msg_ForwardRequest:
-			stepTypeText = "ForwardRequest"
-		case *msgs.Msg_RequestAck:
-			stepTypeText = "RequestAck"
-		default:
-			panic("unknown message type")
-		}
-		if excludeByType(stepTypeText, a.stepTypes, a.notStepTypes) {
+	if step, ok := event.StateEvent.Type.(*state.Event_Step); ok {
+		if excludeByType(msgTypeName(step.Step.Msg), a.stepTypes, a.notStepTypes) {
 			return false
 		}
-	case *state.Event_StateTransferComplete:
-		eventTypeText = "StateTransferComplete"
-	case *state.Event_StateTransferFailed:
-		eventTypeText = "StateTransferFailed"
-	default:
-		panic(fmt.Sprintf("Unknown event type '%T'", event.StateEvent.Type))
 	}
 
 	return true
@@ -326,9 +413,42 @@ func (a *arguments) shouldPrint(event *recording.Event) bool {
 func (a *arguments) execute(output io.Writer) error {
 	defer a.input.Close()
 
+	switch a.command {
+	case "stats":
+		return a.stats(output)
+	case "trace":
+		return a.trace(output)
+	case "timeline":
+		return a.timeline(output)
+	case "export-state":
+		return a.exportState(output)
+	case "merge":
+		return a.merge(output)
+	case "split":
+		return a.split(output)
+	}
+
+	if a.repl {
+		input, ok := a.input.(io.ReadSeeker)
+		if !ok {
+			return errors.Errorf("the REPL requires a seekable input")
+		}
+
+		d, err := newDebugger(input, output, a.logLevel, a.verboseText)
+		if err != nil {
+			return err
+		}
+
+		return d.run(a.commands)
+	}
+
+	logOutput := a.logOutput(output)
+
+	p := newPrinter(a.format, output, a.verboseText)
+
 	// In case of "interactive" mode, events from the
 	// event log will be applied to these state machines.
-	s := newStateMachines(output, a.logLevel)
+	s := newStateMachines(logOutput, a.logLevel)
 
 	// Create log reader.
 	reader, err := eventlog.NewReader(a.input)
@@ -342,6 +462,13 @@ func (a *arguments) execute(output io.Writer) error {
 		statusIndices[index] = struct{}{}
 	}
 
+	// When overriding initial parameters, the replay is compared against
+	// a verbatim one, to detect when it must continue as a simulation.
+	var w *whatIf
+	if a.overrides != nil {
+		w = newWhatIf(a.overrides, a.logLevel)
+	}
+
 	// The log itself does not explicitly keep track of indices,
 	// so we need to keep track of them here.
 	index := uint64(0)
@@ -356,21 +483,26 @@ func (a *arguments) execute(output io.Writer) error {
 		index++
 
 		// Skip event if produced by a node we're not looking at.
-		if excludedByNodeID(event, a.nodeIDs) {
+		// When overriding initial parameters, such events must still be
+		// applied, as the simulation continues every node.
+		excluded := excludedByNodeID(event, a.nodeIDs)
+		if excluded && w == nil {
 			continue
 		}
 
+		recorded := event
+		if w != nil {
+			event = w.overrides.apply(event)
+		}
+
 		// Print event if configured to do so.
 		// We always print the event if the status index matches,
 		// otherwise the output could be quite confusing.
 		_, printStatus := statusIndices[index]
-		if printStatus || a.shouldPrint(event) {
-			text, err := textFormat(event, !a.verboseText)
-			if err != nil {
-				return errors.WithMessage(err, "could not marshal event")
+		if !excluded && (printStatus || a.shouldPrint(event)) {
+			if err := p.event(index, event); err != nil {
+				return err
 			}
-
-			fmt.Fprintf(output, "% 6d %s\n", index, string(text))
 		}
 
 		// If we are in interactive mode, apply event to state machine
@@ -383,46 +515,69 @@ func (a *arguments) execute(output io.Writer) error {
 				return err
 			}
 
-			// Define convenience function for printing the list of resulting actions.
-			printActions := func() error {
-				iter := actions.Iterator()
-				for action := iter.Next(); action != nil; action = iter.Next() {
-					if text, err := textFormat(action, !a.verboseText); err == nil {
-						fmt.Fprintf(output, "       actions: %s\n", string(text))
-					} else {
-						return errors.WithMessage(err, "could not marshal actions")
+			// Compare the replayed actions with the recorded ones, if any.
+			if a.checkDiverge && event.Actions != nil {
+				if diffs := diffActions(event.Actions, actions); len(diffs) > 0 {
+					if err := reportDivergence(logOutput, index, event, diffs, !a.verboseText); err != nil {
+						return errors.WithMessage(err, "could not report divergence")
 					}
+					return errors.Errorf("node %d diverged from recording at index %d", event.NodeId, index)
 				}
-				return nil
 			}
 
 			// Print resulting actions, if:
 			// - this is an event of consuming state machine actions or
 			// - we are configured to print actions on each event application.
-			switch event.StateEvent.Type.(type) {
-			case *state.Event_ActionsReceived:
-				if err := printActions(); err != nil {
+			_, actionsReceived := event.StateEvent.Type.(*state.Event_ActionsReceived)
+			if !excluded && (actionsReceived || a.printActions) {
+				if err := p.actions(index, event, actions); err != nil {
 					return err
 				}
-			default:
-				if a.printActions {
-					if err := printActions(); err != nil {
-						return err
-					}
-				}
 			}
 
 			// Print state machine status if requested for this index.
 			// Note that config options enforce that if printStatus is set, so is interactive
-			if printStatus {
+			if !excluded && printStatus {
 				status, err := s.status(event)
 				if err != nil {
 					return errors.WithMessage(err, "could not retrieve status")
 				}
-				fmt.Fprint(output, status.Pretty())
-				fmt.Fprint(output, "\n")
+				if err := p.status(index, event, status); err != nil {
+					return err
+				}
+			}
+
+			// Once the overridden parameters change the actions, the rest
+			// of the recording no longer applies, and the replay continues
+			// as a simulation.
+			if w != nil {
+				diffs, err := w.observe(recorded, event, actions)
+				if err != nil {
+					return err
+				}
+				if len(diffs) > 0 {
+					if err := p.flush(); err != nil {
+						return errors.WithMessage(err, "could not write output")
+					}
+					if err := reportDivergence(logOutput, index, event, diffs, !a.verboseText); err != nil {
+						return errors.WithMessage(err, "could not report divergence")
+					}
+					fmt.Fprintf(logOutput, "Continuing from index %d as a simulation with the overridden parameters\n", index)
+					if err := a.simulate(w, s, p, index, logOutput); err != nil {
+						return err
+					}
+					break
+				}
 			}
 		}
+
+		if err := p.flush(); err != nil {
+			return errors.WithMessage(err, "could not write output")
+		}
+	}
+
+	if err := p.close(); err != nil {
+		return errors.WithMessage(err, "could not write output")
 	}
 
 	// In interactive mode, print execution times of the nodes.
@@ -441,15 +596,87 @@ func (a *arguments) execute(output io.Writer) error {
 
 		// Print execution time for each node.
 		for _, nodeID := range nodeIDs {
-			fmt.Fprintf(output, "Node %d successfully completed execution in %v\n", nodeID, s.nodes[nodeID].executionTime)
+			fmt.Fprintf(logOutput, "Node %d successfully completed execution in %v\n", nodeID, s.nodes[nodeID].executionTime)
 		}
 	}
 
 	return nil
 }
 
+// logOutput returns the writer for logs and reports which are not part of
+// the output proper.  They are written to stderr when writing JSON, so as
+// not to corrupt it.
+func (a *arguments) logOutput(output io.Writer) io.Writer {
+	if a.format != "" && a.format != formatText {
+		return os.Stderr
+	}
+	return output
+}
+
+func (a *arguments) stats(output io.Writer) error {
+	s, err := collectStats(a.input, a.logOutput(output), a.logLevel)
+	if err != nil {
+		return err
+	}
+
+	if a.format != "" && a.format != formatText {
+		return s.writeJSON(output)
+	}
+
+	s.writeText(output)
+	return nil
+}
+
+func (a *arguments) trace(output io.Writer) error {
+	timelines, err := collectTrace(a.input, a.logOutput(output), a.logLevel, a.traceTarget)
+	if err != nil {
+		return err
+	}
+
+	if a.format != "" && a.format != formatText {
+		return writeTraceJSON(output, timelines, a.format)
+	}
+
+	return writeTraceText(output, a.traceTarget, timelines, !a.verboseText)
+}
+
+func (a *arguments) timeline(output io.Writer) error {
+	trace, err := collectTimeline(a.input, os.Stderr, a.logLevel, !a.verboseText)
+	if err != nil {
+		return err
+	}
+
+	return trace.write(output)
+}
+
 func parseArgs(args []string) (*arguments, error) {
 	app := kingpin.New("mircat", "Utility for processing Mir state event logs.")
+	app.Command("play", "Print the events of the log, optionally applying them to Mir state machines.").Default()
+	app.Command("stats", "Apply the log to Mir state machines, summarizing its events, messages, epochs, checkpoints, and commits.")
+	trace := app.Command("trace", "Apply the log to Mir state machines, printing a per node timeline of the events and actions for a sequence or request.")
+	target := &traceTarget{}
+	traceSeqNo := trace.Flag("seqNo", "The sequence number to trace.").Action(func(*kingpin.ParseContext) error {
+		target.seqNo = new(uint64)
+		return nil
+	}).Uint64()
+	traceClientID := trace.Flag("client", "The client ID of the request to trace. (Must combine with --reqNo)").Action(func(*kingpin.ParseContext) error {
+		target.clientID = new(uint64)
+		return nil
+	}).Uint64()
+	traceReqNo := trace.Flag("reqNo", "The request number of the request to trace. (Must combine with --client)").Action(func(*kingpin.ParseContext) error {
+		target.reqNo = new(uint64)
+		return nil
+	}).Uint64()
+	app.Command("timeline", "Apply the log to Mir state machines, exporting a per node timeline of events, epochs, checkpoint windows, and message flows in the Chrome trace-event format.")
+	exportState := app.Command("export-state", "Apply the log to Mir state machines, writing the WAL and request store directories of a node as they were at an index of the log.")
+	exportNodeID := exportState.Flag("node", "The node whose state to export.").Required().Uint64()
+	exportIndex := exportState.Flag("index", "The index in the log at which to export the state.").Required().Uint64()
+	exportOutput := exportState.Flag("output", "The directory in which to create the 'wal' and 'reqstore' directories.").Required().String()
+	exportHasher := exportState.Flag("hasher", "The hash of the request digests, with which to verify the request data, or 'none' to export only the data retained by the log, unverified.").Default("sha256").Enum("sha256", "sha512", "none")
+	merge := app.Command("merge", "Merge per node logs into a single log ordered by time, writing it to stdout. Events of equal time are taken from the earlier input, and the events of each input retain their order.")
+	mergeInputs := merge.Arg("inputs", "The logs to merge.").Required().ExistingFiles()
+	split := app.Command("split", "Split a log into a log per node.")
+	splitOutput := split.Flag("output", "The directory in which to write the log of each node.").Required().String()
 	input := app.Flag("input", "The input file to read (defaults to stdin).").Default(os.Stdin.Name()).File()
 	interactive := app.Flag("interactive", "Whether to apply this log to a Mir state machine.").Default("false").Bool()
 	printActions := app.Flag("printActions", "Print actions produced by each event. (Must combine with --interactive)").Default("false").Bool()
@@ -461,12 +688,56 @@ func parseArgs(args []string) (*arguments, error) {
 	verboseText := app.Flag("verboseText", "Whether to be verbose (output full bytes) in the text frmatting.").Default("false").Bool()
 	statusIndices := app.Flag("statusIndex", "Print node status at given index in the log (repeatable).").Uint64List()
 	logLevel := app.Flag("logLevel", "When run in interactive mode, the log level for the state machine with which to output.").Enum("debug", "info", "warn", "error")
-
-	_, err := app.Parse(args)
+	checkDiverge := app.Flag("checkDivergence", "Stop at the first event where the replayed actions differ from those recorded. (Must combine with --interactive)").Default("false").Bool()
+	overrides := &initOverrides{}
+	overrideFlag := func(name, help string, override **uint32) {
+		var value *uint32
+		value = app.Flag(name, help+" Once the replayed actions diverge, the replay continues as a simulation. (Must combine with --interactive)").Action(func(*kingpin.ParseContext) error {
+			*override = value
+			return nil
+		}).Uint32()
+	}
+	overrideFlag("batchSize", "Replace the batch size of Initialize events.", &overrides.batchSize)
+	overrideFlag("heartbeatTicks", "Replace the heartbeat ticks of Initialize events.", &overrides.heartbeatTicks)
+	overrideFlag("suspectTicks", "Replace the suspect ticks of Initialize events.", &overrides.suspectTicks)
+	overrideFlag("newEpochTimeoutTicks", "Replace the new epoch timeout ticks of Initialize events.", &overrides.newEpochTimeoutTicks)
+	overrideFlag("bufferSize", "Replace the buffer size of Initialize events.", &overrides.bufferSize)
+	simulateSteps := app.Flag("simulateSteps", "The maximum number of steps to simulate once the replay with replaced parameters diverges.").Default("100000").Int()
+	format := app.Flag("format", "The output format, JSON formats encode each event, and its actions and status, as protojson.").Default(formatText).Enum(allFormats...)
+	repl := app.Flag("repl", "Step through the log in an interactive debugger reading commands from stdin, try 'help'. (Requires --input)").Default("false").Bool()
+
+	command, err := app.Parse(args)
 	if err != nil {
 		return nil, err
 	}
 
+	if command == "trace" {
+		switch {
+		case target.seqNo != nil && (target.clientID != nil || target.reqNo != nil):
+			return nil, errors.Errorf("cannot trace both a sequence and a request")
+		case target.seqNo != nil:
+			*target.seqNo = *traceSeqNo
+		case target.clientID != nil && target.reqNo != nil:
+			*target.clientID = *traceClientID
+			*target.reqNo = *traceReqNo
+		default:
+			return nil, errors.Errorf("trace requires either --seqNo, or --client and --reqNo")
+		}
+	}
+
+	var export *exportTarget
+	if command == "export-state" {
+		if *exportIndex == 0 {
+			return nil, errors.Errorf("export-state requires an --index of at least 1")
+		}
+		export = &exportTarget{
+			nodeID: *exportNodeID,
+			index:  *exportIndex,
+			output: *exportOutput,
+			hasher: exportHashers[*exportHasher],
+		}
+	}
+
 	switch {
 	case *eventTypes != nil && *notEventTypes != nil:
 		return nil, errors.Errorf("cannot set both --eventType and --notEventType")
@@ -474,10 +745,26 @@ func parseArgs(args []string) (*arguments, error) {
 		return nil, errors.Errorf("cannot set both --stepType and --notStepType")
 	case *statusIndices != nil && !*interactive:
 		return nil, errors.Errorf("cannot set status indices for non-interactive playback")
-	case *logLevel != "" && !*interactive:
+	case *repl && (*input).Name() == os.Stdin.Name():
+		return nil, errors.Errorf("cannot use the REPL with stdin as input")
+	case *repl && *format != formatText:
+		return nil, errors.Errorf("cannot use the REPL with JSON output")
+	case *logLevel != "" && !*interactive && !*repl && command == "play":
 		return nil, errors.Errorf("cannot set logLevel for non-interactive playback")
 	case *printActions && !*interactive:
 		return nil, errors.Errorf("cannot print actions for non-interactive playback")
+	case *checkDiverge && !*interactive:
+		return nil, errors.Errorf("cannot check divergence for non-interactive playback")
+	case overrides.set() && (command != "play" || !*interactive):
+		return nil, errors.Errorf("cannot replace initial parameters for non-interactive playback")
+	case overrides.set() && *checkDiverge:
+		return nil, errors.Errorf("cannot check divergence from the recording when replacing initial parameters")
+	case overrides.set() && *repl:
+		return nil, errors.Errorf("cannot replace initial parameters in the REPL")
+	}
+
+	if !overrides.set() {
+		overrides = nil
 	}
 
 	mirLogLevel := statemachine.LevelInfo
@@ -494,6 +781,11 @@ func parseArgs(args []string) (*arguments, error) {
 	}
 
 	return &arguments{
+		command:       command,
+		traceTarget:   target,
+		exportTarget:  export,
+		mergeInputs:   *mergeInputs,
+		splitOutput:   *splitOutput,
 		input:         *input,
 		interactive:   *interactive,
 		printActions:  *printActions,
@@ -505,6 +797,12 @@ func parseArgs(args []string) (*arguments, error) {
 		notStepTypes:  *notStepTypes,
 		verboseText:   *verboseText,
 		statusIndices: *statusIndices,
+		checkDiverge:  *checkDiverge,
+		overrides:     overrides,
+		simulateSteps: *simulateSteps,
+		format:        *format,
+		repl:          *repl,
+		commands:      os.Stdin,
 	}, nil
 }
 
@@ -3,14 +3,55 @@ package main
 import (
 	"bytes"
 	"compress/gzip"
+	"container/list"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
 
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 	"github.com/hyperledger-labs/mirbft/pkg/testengine"
 )
 
+// newTestRecording records a four node network until its clients drain,
+// returning the gzipped log and the recording.
+func newTestRecording(tweak func(*testengine.Recorder)) (*bytes.Buffer, *testengine.Recording) {
+	logBytes := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(logBytes)
+
+	recorder := (&testengine.Spec{
+		NodeCount:     4,
+		ClientCount:   4,
+		ReqsPerClient: 20,
+		TweakRecorder: tweak,
+	}).Recorder()
+	recorder.LogOutput = ioutil.Discard
+
+	recording, err := recorder.Recording(gzWriter)
+	Expect(err).NotTo(HaveOccurred())
+
+	_, err = recording.DrainClients(5000)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(gzWriter.Close()).To(Succeed())
+
+	return logBytes, recording
+}
+
 var _ = Describe("Parsing", func() {
 	var (
 		logBytes *bytes.Buffer
@@ -47,6 +88,7 @@ var _ = Describe("Parsing", func() {
 			"--statusIndex", "301",
 			"--statusIndex", "305",
 			"--verboseText",
+			"--format", "jsonl",
 		})
 		Expect(err).NotTo(HaveOccurred())
 		Expect(args.input).NotTo(BeNil())
@@ -56,6 +98,57 @@ var _ = Describe("Parsing", func() {
 		Expect(args.eventTypes).To(Equal([]string{"Step", "Tick"}))
 		Expect(args.statusIndices).To(Equal([]uint64{301, 305}))
 		Expect(args.verboseText).To(BeTrue())
+		Expect(args.format).To(Equal("jsonl"))
+		Expect(args.command).To(Equal("play"))
+	})
+
+	It("parses the stats command", func() {
+		args, err := parseArgs([]string{
+			"stats",
+			"--input", "main.go",
+			"--logLevel", "error",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("stats"))
+		Expect(args.logLevel).To(Equal(statemachine.LevelError))
+	})
+
+	It("parses the trace command", func() {
+		args, err := parseArgs([]string{
+			"trace",
+			"--input", "main.go",
+			"--client", "2",
+			"--reqNo", "7",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("trace"))
+		Expect(args.traceTarget.seqNo).To(BeNil())
+		Expect(*args.traceTarget.clientID).To(Equal(uint64(2)))
+		Expect(*args.traceTarget.reqNo).To(Equal(uint64(7)))
+	})
+
+	When("trace has no target", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"trace",
+				"--client", "2",
+			})
+			Expect(err).To(MatchError("trace requires either --seqNo, or --client and --reqNo"))
+		})
+	})
+
+	When("trace has both a sequence and a request target", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"trace",
+				"--seqNo", "0",
+				"--client", "2",
+				"--reqNo", "7",
+			})
+			Expect(err).To(MatchError("cannot trace both a sequence and a request"))
+		})
 	})
 
 	When("both event includes and event excludes are present", func() {
@@ -86,6 +179,112 @@ var _ = Describe("Parsing", func() {
 			Expect(err).To(MatchError("cannot set status indices for non-interactive playback"))
 		})
 	})
+
+	It("parses initial parameter overrides", func() {
+		args, err := parseArgs([]string{
+			"--input", "main.go",
+			"--interactive",
+			"--batchSize", "4",
+			"--suspectTicks", "0",
+			"--simulateSteps", "500",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.overrides).NotTo(BeNil())
+		Expect(*args.overrides.batchSize).To(Equal(uint32(4)))
+		Expect(*args.overrides.suspectTicks).To(Equal(uint32(0)))
+		Expect(args.overrides.heartbeatTicks).To(BeNil())
+		Expect(args.overrides.newEpochTimeoutTicks).To(BeNil())
+		Expect(args.overrides.bufferSize).To(BeNil())
+		Expect(args.simulateSteps).To(Equal(500))
+	})
+
+	When("initial parameters are overridden, but interactive is not", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"--heartbeatTicks", "1",
+			})
+			Expect(err).To(MatchError("cannot replace initial parameters for non-interactive playback"))
+		})
+	})
+
+	When("initial parameters are overridden while checking divergence", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"--interactive",
+				"--checkDivergence",
+				"--bufferSize", "1024",
+			})
+			Expect(err).To(MatchError("cannot check divergence from the recording when replacing initial parameters"))
+		})
+	})
+	It("parses the export-state command", func() {
+		args, err := parseArgs([]string{
+			"export-state",
+			"--input", "main.go",
+			"--node", "2",
+			"--index", "500",
+			"--output", "/tmp/node2",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("export-state"))
+		Expect(args.exportTarget).To(Equal(&exportTarget{
+			nodeID: 2,
+			index:  500,
+			output: "/tmp/node2",
+			hasher: crypto.SHA256,
+		}))
+	})
+
+	It("parses the export-state hasher", func() {
+		args, err := parseArgs([]string{
+			"export-state",
+			"--input", "main.go",
+			"--node", "2",
+			"--index", "500",
+			"--output", "/tmp/node2",
+			"--hasher", "none",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.exportTarget.hasher).To(BeNil())
+	})
+
+	It("parses the merge command", func() {
+		args, err := parseArgs([]string{
+			"merge",
+			"main.go",
+			"main_test.go",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("merge"))
+		Expect(args.mergeInputs).To(Equal([]string{"main.go", "main_test.go"}))
+	})
+
+	It("parses the split command", func() {
+		args, err := parseArgs([]string{
+			"split",
+			"--input", "main.go",
+			"--output", "/tmp/split",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(args.input.Close()).NotTo(HaveOccurred())
+		Expect(args.command).To(Equal("split"))
+		Expect(args.splitOutput).To(Equal("/tmp/split"))
+	})
+
+	When("export-state has no index", func() {
+		It("returns an error", func() {
+			_, err := parseArgs([]string{
+				"export-state",
+				"--node", "2",
+				"--output", "/tmp/node2",
+			})
+			Expect(err).To(MatchError("required flag --index not provided"))
+		})
+	})
 })
 
 var _ = Describe("Execution", func() {
@@ -128,4 +327,727 @@ var _ = Describe("Execution", func() {
 		Expect(output.String()).To(ContainSubstring("1 [node_id=0 time=10 state_event=[initialize=[id=0 batch_size=1 heartbeat_ticks=2 suspect_ticks=4 new_epoch_timeout_ticks=8 buffer_size=5242880]]]"))
 		Expect(output.String()).To(ContainSubstring("4 [node_id=0 time=10 state_event=[complete_initialization=[]]]"))
 	})
+
+	It("writes JSON lines", func() {
+		args.format = "jsonl"
+		args.statusIndices = []uint64{4}
+		args.logLevel = statemachine.LevelError
+		Expect(args.execute(output)).To(Succeed())
+
+		var records []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			var record map[string]interface{}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			records = append(records, record)
+		}
+
+		Expect(records[0]["index"]).To(BeEquivalentTo(1))
+		Expect(records[0]["node_id"]).To(BeEquivalentTo(0))
+		Expect(records[0]["event"]).To(HaveKeyWithValue("state_event", HaveKeyWithValue("initialize", HaveKeyWithValue("batch_size", BeEquivalentTo(1)))))
+
+		Expect(records[1]["index"]).To(BeEquivalentTo(4))
+		Expect(records[1]["event"]).To(HaveKeyWithValue("state_event", HaveKey("complete_initialization")))
+		Expect(records[1]["status"]).To(HaveKeyWithValue("node_id", BeEquivalentTo(0)))
+
+		var withActions int
+		for _, record := range records {
+			if _, ok := record["actions"]; ok {
+				withActions++
+			}
+		}
+		Expect(withActions).NotTo(BeZero())
+	})
+
+	It("writes a JSON array", func() {
+		args.format = "json"
+		args.logLevel = statemachine.LevelError
+		Expect(args.execute(output)).To(Succeed())
+
+		var records []map[string]interface{}
+		Expect(json.Unmarshal(output.Bytes(), &records)).To(Succeed())
+		Expect(records).NotTo(BeEmpty())
+		Expect(records[0]["index"]).To(BeEquivalentTo(1))
+	})
+})
+
+var _ = Describe("Stats", func() {
+	var (
+		args   *arguments
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		logBytes, _ := newTestRecording(nil)
+		output = &bytes.Buffer{}
+
+		args = &arguments{
+			command:  "stats",
+			input:    ioutil.NopCloser(logBytes),
+			logLevel: statemachine.LevelError,
+		}
+	})
+
+	It("summarizes the recording", func() {
+		Expect(args.execute(output)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("Recording: "))
+		Expect(output.String()).To(MatchRegexp(`node 2: .*Preprepare=80 .*RequestAck=320`))
+		Expect(output.String()).To(MatchRegexp(`node 1: 1@\d+\(change=\d+\)`))
+		Expect(output.String()).To(MatchRegexp(`node 3: 0@\d+ 20@\d+ 40@\d+`))
+		Expect(output.String()).To(ContainSubstring("Commits:\n  node 0: 80\n"))
+		Expect(output.String()).To(MatchRegexp(`count=320 min=\d+ mean=\d+ p50=\d+ p99=\d+ max=\d+`))
+		Expect(output.String()).To(ContainSubstring("State transfers:\n  none\n"))
+	})
+
+	It("summarizes the recording as JSON", func() {
+		args.format = "json"
+		Expect(args.execute(output)).To(Succeed())
+
+		var s stats
+		Expect(json.Unmarshal(output.Bytes(), &s)).To(Succeed())
+		Expect(s.Nodes).To(HaveLen(4))
+		Expect(s.Nodes[0].Commits).To(Equal(80))
+		Expect(s.Nodes[0].Epochs).To(HaveLen(1))
+		Expect(s.MessageTypes["Commit"].Count).To(Equal(4 * 4 * 80))
+		Expect(s.CommitLatency.Count).To(Equal(320))
+		Expect(s.CommitLatency.Min).To(BeNumerically("<=", s.CommitLatency.P50))
+	})
+})
+
+var _ = Describe("Trace", func() {
+	var (
+		args   *arguments
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		logBytes, _ := newTestRecording(nil)
+		output = &bytes.Buffer{}
+
+		args = &arguments{
+			command:  "trace",
+			input:    ioutil.NopCloser(logBytes),
+			logLevel: statemachine.LevelError,
+		}
+	})
+
+	It("traces a sequence number across nodes", func() {
+		seqNo := uint64(5)
+		args.traceTarget = &traceTarget{seqNo: &seqNo}
+		Expect(args.execute(output)).To(Succeed())
+		Expect(output.String()).To(HavePrefix("Trace of seq_no 5 across 4 nodes\n"))
+		Expect(output.String()).To(ContainSubstring("\nnode 3:\n"))
+		Expect(output.String()).To(MatchRegexp(`event  \[step=\[source=\d msg=\[preprepare=\[seq_no=5 `))
+		Expect(output.String()).To(MatchRegexp(`action \[append_write_ahead=\[index=\d+ data=\[q_entry=\[seq_no=5 `))
+		Expect(output.String()).To(MatchRegexp(`action \[append_write_ahead=\[index=\d+ data=\[p_entry=\[seq_no=5 `))
+		Expect(output.String()).To(MatchRegexp(`action \[commit=\[batch=\[seq_no=5 `))
+		Expect(output.String()).NotTo(ContainSubstring("seq_no=6 "))
+	})
+
+	It("traces a client request across nodes", func() {
+		clientID, reqNo := uint64(1), uint64(3)
+		args.traceTarget = &traceTarget{clientID: &clientID, reqNo: &reqNo}
+		Expect(args.execute(output)).To(Succeed())
+		Expect(output.String()).To(HavePrefix("Trace of client 1 req_no 3 across 4 nodes\n"))
+		Expect(output.String()).To(ContainSubstring("action [allocated_request=[client_id=1 req_no=3]]"))
+		Expect(output.String()).To(ContainSubstring("event  [request_persisted=[request_ack=[client_id=1 req_no=3 "))
+		Expect(output.String()).To(MatchRegexp(`action \[commit=\[batch=\[seq_no=\d+ digest=\w+ requests=\[client_id=1 req_no=3 `))
+	})
+
+	It("writes the trace as JSON lines ordered by time per node", func() {
+		seqNo := uint64(5)
+		args.traceTarget = &traceTarget{seqNo: &seqNo}
+		args.format = "jsonl"
+		Expect(args.execute(output)).To(Succeed())
+
+		lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+		Expect(len(lines)).To(BeNumerically(">", 4))
+
+		var last traceEntry
+		for i, line := range lines {
+			var entry traceEntry
+			Expect(json.Unmarshal([]byte(line), &entry)).To(Succeed())
+			Expect(entry.Kind).To(Or(Equal("event"), Equal("action")))
+			Expect(string(entry.Item)).To(ContainSubstring(`"seq_no":"5"`))
+			if i > 0 && entry.NodeID == last.NodeID {
+				Expect(entry.Time).To(BeNumerically(">=", last.Time))
+			} else if i > 0 {
+				Expect(entry.NodeID).To(BeNumerically(">", last.NodeID))
+			}
+			last = entry
+		}
+	})
+})
+
+var _ = Describe("Timeline", func() {
+	var (
+		args   *arguments
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		logBytes, _ := newTestRecording(nil)
+		output = &bytes.Buffer{}
+
+		args = &arguments{
+			command:  "timeline",
+			input:    ioutil.NopCloser(logBytes),
+			logLevel: statemachine.LevelError,
+		}
+	})
+
+	It("exports a Chrome trace", func() {
+		Expect(args.execute(output)).To(Succeed())
+
+		var trace chromeTrace
+		Expect(json.Unmarshal(output.Bytes(), &trace)).To(Succeed())
+		Expect(trace.DisplayTimeUnit).To(Equal("ms"))
+
+		processes := map[string]bool{}
+		spans := map[string]map[int]int{}
+		steps, flowStarts, flowEnds := 0, map[uint64]*chromeEvent{}, map[uint64]*chromeEvent{}
+		for _, event := range trace.TraceEvents {
+			switch event.Ph {
+			case "M":
+				if event.Name == "process_name" {
+					processes[event.Args["name"].(string)] = true
+				}
+			case "X":
+				Expect(event.Dur).NotTo(BeNil())
+				Expect(*event.Dur).To(BeNumerically(">=", 0))
+				if spans[event.Cat] == nil {
+					spans[event.Cat] = map[int]int{}
+				}
+				spans[event.Cat][event.Tid]++
+				if strings.HasPrefix(event.Name, "Step ") {
+					steps++
+				}
+			case "s":
+				flowStarts[event.ID] = event
+			case "f":
+				flowEnds[event.ID] = event
+			}
+		}
+
+		Expect(processes).To(Equal(map[string]bool{"node 0": true, "node 1": true, "node 2": true, "node 3": true}))
+		Expect(spans["event"]).To(HaveKey(timelineEventsTid))
+		Expect(spans["epoch"]).To(HaveKey(timelineEpochsTid))
+		Expect(spans["checkpoint"]).To(HaveKey(timelineWindowsTid))
+		Expect(spans["checkpoint"][timelineWindowsTid]).To(BeNumerically(">", 4))
+
+		Expect(steps).To(BeNumerically(">", 0))
+		Expect(flowStarts).To(HaveLen(steps))
+		Expect(flowEnds).To(HaveLen(steps))
+		for id, start := range flowStarts {
+			Expect(flowEnds[id].Ts).To(BeNumerically(">", start.Ts))
+			Expect(flowEnds[id].Name).To(Equal(start.Name))
+		}
+	})
+
+	It("names epoch spans by number and state", func() {
+		Expect(args.execute(output)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring(`"name":"epoch 1 in_progress","cat":"epoch","ph":"X"`))
+		Expect(output.String()).To(MatchRegexp(`"name":"window 1-\d+","cat":"checkpoint"`))
+	})
+})
+
+var _ = Describe("Debugger", func() {
+	var (
+		output *bytes.Buffer
+		args   *arguments
+	)
+
+	BeforeEach(func() {
+		logBytes, _ := newTestRecording(nil)
+		output = &bytes.Buffer{}
+
+		args = &arguments{
+			input:    &readSeekCloser{Reader: bytes.NewReader(logBytes.Bytes())},
+			repl:     true,
+			logLevel: statemachine.LevelError,
+		}
+	})
+
+	run := func(commands ...string) string {
+		args.commands = strings.NewReader(strings.Join(commands, "\n"))
+		Expect(args.execute(output)).To(Succeed())
+		return output.String()
+	}
+
+	It("steps through events", func() {
+		out := run("step 4", "status 0", "quit")
+		Expect(out).To(ContainSubstring("1 [node_id=0 time=10 state_event=[initialize=[id=0 batch_size=1"))
+		Expect(out).To(ContainSubstring("4 [node_id=0 time=10 state_event=[complete_initialization=[]]]"))
+		Expect(out).To(ContainSubstring("NodeID=0,"))
+	})
+
+	It("continues to breakpoints", func() {
+		out := run("break node=2 msg=Commit seqno=3", "continue", "actions", "quit")
+		Expect(out).To(ContainSubstring("breakpoint 1 (node=2 msg=Commit seqno=3) hit"))
+		Expect(out).To(MatchRegexp(`node_id=2 .*step=\[source=\d msg=\[commit=\[seq_no=3 `))
+	})
+
+	It("deletes breakpoints", func() {
+		out := run("break node=0", "break msg=Prepare epoch=1", "delete 1", "breakpoints", "continue", "quit")
+		Expect(out).NotTo(ContainSubstring("breakpoint 1 (node=0) hit"))
+		Expect(out).To(ContainSubstring("breakpoint 2 (msg=Prepare epoch=1) hit"))
+		Expect(out).To(MatchRegexp(`prepare=\[seq_no=\d+ epoch=1 `))
+	})
+
+	It("breaks on status predicates", func() {
+		out := run("break node=1 status=checkpoint-stable", "continue", "status", "quit")
+		Expect(out).To(ContainSubstring("breakpoint 1 (node=1 status=checkpoint-stable) hit"))
+		Expect(out).To(ContainSubstring("NodeID=1,"))
+	})
+
+	It("rewinds by replaying", func() {
+		out := run("goto 20", "event", "back 15", "goto 20", "quit")
+		Expect(strings.Count(out, "    20 [")).To(Equal(3))
+		Expect(out).To(ContainSubstring("     5 ["))
+	})
+
+	It("reports invalid commands and continues", func() {
+		out := run("bogus", "break color=red", "delete 7", "step", "quit")
+		Expect(out).To(ContainSubstring(`error: unknown command "bogus", try help`))
+		Expect(out).To(ContainSubstring(`error: unknown condition "color"`))
+		Expect(out).To(ContainSubstring("error: no breakpoint 7"))
+		Expect(out).To(ContainSubstring("     1 [node_id=0"))
+	})
 })
+
+var _ = Describe("Divergence", func() {
+	var (
+		events []*recording.Event
+		output *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		logBytes, _ := newTestRecording(func(r *testengine.Recorder) {
+			r.RecordActions = true
+		})
+		output = &bytes.Buffer{}
+
+		reader, err := eventlog.NewReader(logBytes)
+		Expect(err).NotTo(HaveOccurred())
+		events = nil
+		for event, err := reader.ReadEvent(); err != io.EOF; event, err = reader.ReadEvent() {
+			Expect(err).NotTo(HaveOccurred())
+			events = append(events, event)
+		}
+	})
+
+	execute := func() error {
+		logBytes := &bytes.Buffer{}
+		gzWriter := gzip.NewWriter(logBytes)
+		for _, event := range events {
+			Expect(eventlog.WriteRecordedEvent(gzWriter, event)).To(Succeed())
+		}
+		Expect(gzWriter.Close()).To(Succeed())
+
+		return (&arguments{
+			input:        ioutil.NopCloser(logBytes),
+			interactive:  true,
+			checkDiverge: true,
+			logLevel:     statemachine.LevelError,
+			eventTypes:   []string{},
+		}).execute(output)
+	}
+
+	It("replays without divergence", func() {
+		Expect(execute()).To(Succeed())
+		Expect(output.String()).NotTo(ContainSubstring("diverge"))
+	})
+
+	It("reports the first divergent event", func() {
+		var tampered int
+		for i, event := range events {
+			if event.Actions == nil || len(event.Actions.List) == 0 {
+				continue
+			}
+
+			for _, action := range event.Actions.List {
+				if write, ok := action.Type.(*state.Action_AppendWriteAhead); ok {
+					write.AppendWriteAhead.Index += 100
+					tampered = i + 1
+					break
+				}
+			}
+
+			if tampered != 0 {
+				break
+			}
+		}
+		Expect(tampered).NotTo(BeZero())
+
+		err := execute()
+		Expect(err).To(MatchError(fmt.Sprintf("node %d diverged from recording at index %d", events[tampered-1].NodeId, tampered)))
+		Expect(output.String()).To(ContainSubstring(fmt.Sprintf("% 6d node %d replayed actions diverge from recording at 1 position(s)", tampered, events[tampered-1].NodeId)))
+		Expect(output.String()).To(ContainSubstring("append_write_ahead.index: "))
+	})
+})
+
+var _ = Describe("WhatIf", func() {
+	var (
+		logBytes *bytes.Buffer
+		output   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		logBytes, _ = newTestRecording(nil)
+		output = &bytes.Buffer{}
+	})
+
+	execute := func(overrides *initOverrides) error {
+		return (&arguments{
+			input:         ioutil.NopCloser(logBytes),
+			interactive:   true,
+			logLevel:      statemachine.LevelError,
+			eventTypes:    []string{"Initialize"},
+			overrides:     overrides,
+			simulateSteps: 5000,
+		}).execute(output)
+	}
+
+	It("replays the recording while the actions do not diverge", func() {
+		bufferSize := uint32(10 * 1024 * 1024)
+		Expect(execute(&initOverrides{bufferSize: &bufferSize})).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("buffer_size=10485760"))
+		Expect(output.String()).NotTo(ContainSubstring("diverge"))
+		Expect(output.String()).NotTo(ContainSubstring("Simulation"))
+	})
+
+	It("continues as a simulation from the first divergence", func() {
+		batchSize := uint32(4)
+		Expect(execute(&initOverrides{batchSize: &batchSize})).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("batch_size=4"))
+		Expect(output.String()).To(MatchRegexp(`(?m)^ +\d+ node \d replayed actions diverge from recording`))
+		Expect(output.String()).To(MatchRegexp(`Continuing from index \d+ as a simulation with the overridden parameters`))
+		Expect(output.String()).To(MatchRegexp(`Simulation ended after \d+ steps, as the clients drained`))
+
+		// Four requests are committed in some simulated batch.
+		Expect(output.String()).To(MatchRegexp(`commit=\[batch=\[seq_no=\d+ digest=\w+( requests=\[[^]]*\]){4}`))
+	})
+
+	It("stops the simulation at the step limit", func() {
+		batchSize := uint32(4)
+		Expect((&arguments{
+			input:         ioutil.NopCloser(logBytes),
+			interactive:   true,
+			logLevel:      statemachine.LevelError,
+			eventTypes:    []string{},
+			overrides:     &initOverrides{batchSize: &batchSize},
+			simulateSteps: 10,
+		}).execute(output)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("Simulation ended after 10 steps, as the step limit was reached"))
+	})
+})
+
+var _ = Describe("ExportState", func() {
+	var (
+		logBytes *bytes.Buffer
+		output   *bytes.Buffer
+		rec      *testengine.Recording
+		eventLen uint64
+		tmpDir   string
+	)
+
+	BeforeEach(func() {
+		logBytes, rec = newTestRecording(nil)
+		output = &bytes.Buffer{}
+
+		reader, err := eventlog.NewReader(bytes.NewReader(logBytes.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		eventLen = 0
+		for _, err := reader.ReadEvent(); err != io.EOF; _, err = reader.ReadEvent() {
+			Expect(err).NotTo(HaveOccurred())
+			eventLen++
+		}
+
+		tmpDir, err = ioutil.TempDir("", "mircat-export-*")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	execute := func(nodeID, index uint64) error {
+		return (&arguments{
+			command:  "export-state",
+			input:    ioutil.NopCloser(bytes.NewReader(logBytes.Bytes())),
+			logLevel: statemachine.LevelError,
+			exportTarget: &exportTarget{
+				nodeID: nodeID,
+				index:  index,
+				output: tmpDir,
+				hasher: crypto.SHA256,
+			},
+		}).execute(output)
+	}
+
+	It("writes the WAL and request store of the node", func() {
+		Expect(execute(1, eventLen)).To(Succeed())
+		Expect(output.String()).To(MatchRegexp(`Wrote WAL entries \d+-\d+ of node 1 at index \d+ to `))
+		Expect(output.String()).To(MatchRegexp(`Wrote \d+ requests to `))
+		Expect(output.String()).NotTo(ContainSubstring("omitted"))
+
+		wal, err := simplewal.Open(filepath.Join(tmpDir, "wal"))
+		Expect(err).NotTo(HaveOccurred())
+		defer wal.Close()
+
+		exported := map[uint64]*msgs.Persistent{}
+		Expect(wal.LoadAll(func(index uint64, p *msgs.Persistent) {
+			exported[index] = p
+		})).To(Succeed())
+
+		// The testengine may not yet have processed the last WAL actions
+		// the node received, so its WAL is a prefix of the exported one.
+		node := rec.Nodes[1]
+		Expect(exported).To(HaveKey(node.WAL.LowIndex))
+		Expect(exported).NotTo(HaveKey(node.WAL.LowIndex - 1))
+		Expect(node.WAL.LoadAll(func(index uint64, p *msgs.Persistent) {
+			Expect(proto.Equal(exported[index], p)).To(BeTrue())
+		})).To(Succeed())
+
+		events, err := processor.RecoverWALForExistingNode(wal, node.Config.InitParms)
+		Expect(err).NotTo(HaveOccurred())
+		sm := &statemachine.StateMachine{
+			Logger: statemachine.ConsoleErrorLogger,
+		}
+		iter := events.Iterator()
+		for event := iter.Next(); event != nil; event = iter.Next() {
+			sm.ApplyEvent(event)
+		}
+		Expect(sm.Facets().EpochNumber).To(Equal(rec.Nodes[1].StateMachine.Facets().EpochNumber))
+
+		store, err := reqstore.Open(filepath.Join(tmpDir, "reqstore"))
+		Expect(err).NotTo(HaveOccurred())
+		defer store.Close()
+
+		// The requests below the low watermark of the stable checkpoint
+		// are no longer needed by the node.
+		var lowWatermark uint64
+		stableSeqNo := rec.Nodes[1].StateMachine.Facets().StableCheckpoint
+		for _, p := range exported {
+			if cEntry := p.GetCEntry(); cEntry != nil && cEntry.SeqNo == stableSeqNo {
+				lowWatermark = cEntry.NetworkState.Clients[2].LowWatermark
+			}
+		}
+		Expect(lowWatermark).To(BeNumerically(">", 0))
+		Expect(store.GetAllocation(2, lowWatermark-1)).To(BeNil())
+
+		data := testengine.ClientRequestData(2, lowWatermark)
+		h := crypto.SHA256.New()
+		h.Write(data)
+		digest := h.Sum(nil)
+		Expect(store.GetAllocation(2, lowWatermark)).To(Equal(digest))
+		Expect(store.GetRequest(&msgs.RequestAck{
+			ClientId: 2,
+			ReqNo:    lowWatermark,
+			Digest:   digest,
+		})).To(Equal(data))
+	})
+
+	It("does not apply the events of other nodes", func() {
+		reader, err := eventlog.NewReader(logBytes)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Node 9 never initializes, so its tick cannot be applied.
+		logBytes = &bytes.Buffer{}
+		gzWriter := gzip.NewWriter(logBytes)
+		Expect(eventlog.WriteRecordedEvent(gzWriter, &recording.Event{
+			NodeId:     9,
+			StateEvent: (&statemachine.EventList{}).TickElapsed().Iterator().Next(),
+		})).To(Succeed())
+		for event, err := reader.ReadEvent(); err != io.EOF; event, err = reader.ReadEvent() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(eventlog.WriteRecordedEvent(gzWriter, event)).To(Succeed())
+		}
+		Expect(gzWriter.Close()).To(Succeed())
+
+		Expect(execute(1, eventLen+1)).To(Succeed())
+		Expect(output.String()).To(MatchRegexp(`Wrote WAL entries \d+-\d+ of node 1 at index \d+ to `))
+	})
+
+	It("uses the request data retained by the recording", func() {
+		data := []byte("retained")
+		h := crypto.SHA256.New()
+		h.Write(data)
+		ack := &msgs.RequestAck{
+			ClientId: 7,
+			ReqNo:    1,
+			Digest:   h.Sum(nil),
+		}
+
+		es := &exportedState{
+			hasher: crypto.SHA256,
+			data:   map[string][]byte{},
+		}
+		persisted := (&statemachine.EventList{}).RequestPersisted(ack).Iterator().Next()
+		Expect(es.observe(&recording.Event{StateEvent: persisted}, nil)).To(Succeed())
+		Expect(es.observe(&recording.Event{StateEvent: persisted, RequestData: data}, nil)).To(Succeed())
+		Expect(es.requests).To(HaveLen(1))
+
+		retained, ok := es.requestData(ack)
+		Expect(ok).To(BeTrue())
+		Expect(retained).To(Equal(data))
+	})
+
+	It("writes WALs beginning past the first index", func() {
+		es := &exportedState{
+			wal: &testengine.WAL{
+				LowIndex: 5,
+				List:     list.New(),
+			},
+		}
+		for seqNo := uint64(1); seqNo <= 3; seqNo++ {
+			es.wal.List.PushBack(&msgs.Persistent{
+				Type: &msgs.Persistent_QEntry{
+					QEntry: &msgs.QEntry{SeqNo: seqNo},
+				},
+			})
+		}
+		Expect(es.writeWAL(filepath.Join(tmpDir, "wal"))).To(Succeed())
+
+		wal, err := simplewal.Open(filepath.Join(tmpDir, "wal"))
+		Expect(err).NotTo(HaveOccurred())
+		defer wal.Close()
+
+		var indices []uint64
+		Expect(wal.LoadAll(func(index uint64, p *msgs.Persistent) {
+			indices = append(indices, index)
+			Expect(p.Type.(*msgs.Persistent_QEntry).QEntry.SeqNo).To(Equal(index - 4))
+		})).To(Succeed())
+		Expect(indices).To(Equal([]uint64{5, 6, 7}))
+	})
+
+	When("the node has not completed initialization by the index", func() {
+		It("returns an error", func() {
+			Expect(execute(3, 1)).To(MatchError("node 3 has not completed initialization by index 1"))
+		})
+	})
+
+	When("the index is beyond the recording", func() {
+		It("returns an error", func() {
+			Expect(execute(1, eventLen+1)).To(MatchError(fmt.Sprintf("recording ends at index %d, before index %d", eventLen, eventLen+1)))
+		})
+	})
+
+	When("the output directory is not empty", func() {
+		It("returns an error", func() {
+			walPath := filepath.Join(tmpDir, "wal")
+			Expect(os.MkdirAll(walPath, 0700)).To(Succeed())
+			Expect(ioutil.WriteFile(filepath.Join(walPath, "00000000000000000001"), nil, 0600)).To(Succeed())
+			Expect(execute(1, eventLen)).To(MatchError(walPath + " is not empty"))
+		})
+	})
+})
+
+var _ = Describe("MergeSplit", func() {
+	var (
+		logBytes *bytes.Buffer
+		output   *bytes.Buffer
+		tmpDir   string
+	)
+
+	BeforeEach(func() {
+		logBytes, _ = newTestRecording(nil)
+		output = &bytes.Buffer{}
+
+		var err error
+		tmpDir, err = ioutil.TempDir("", "mircat-split-*")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	readAll := func(input io.Reader) []*recording.Event {
+		reader, err := eventlog.NewReader(input)
+		Expect(err).NotTo(HaveOccurred())
+
+		var events []*recording.Event
+		for event, err := reader.ReadEvent(); err != io.EOF; event, err = reader.ReadEvent() {
+			Expect(err).NotTo(HaveOccurred())
+			events = append(events, event)
+		}
+		return events
+	}
+
+	split := func() error {
+		return (&arguments{
+			command:     "split",
+			input:       ioutil.NopCloser(bytes.NewReader(logBytes.Bytes())),
+			splitOutput: tmpDir,
+		}).execute(output)
+	}
+
+	It("splits a recording per node, and merges it back", func() {
+		Expect(split()).To(Succeed())
+		Expect(output.String()).To(Equal(fmt.Sprintf("Wrote %[1]s/node0.eventlog.gz\nWrote %[1]s/node1.eventlog.gz\nWrote %[1]s/node2.eventlog.gz\nWrote %[1]s/node3.eventlog.gz\n", tmpDir)))
+
+		var inputs []string
+		for nodeID := uint64(0); nodeID < 4; nodeID++ {
+			inputs = append(inputs, filepath.Join(tmpDir, splitFileName(nodeID)))
+			file, err := os.Open(inputs[nodeID])
+			Expect(err).NotTo(HaveOccurred())
+			for _, event := range readAll(file) {
+				Expect(event.NodeId).To(Equal(nodeID))
+			}
+			Expect(file.Close()).To(Succeed())
+		}
+
+		merged := &bytes.Buffer{}
+		Expect((&arguments{
+			command:     "merge",
+			input:       ioutil.NopCloser(&bytes.Buffer{}),
+			mergeInputs: inputs,
+		}).execute(merged)).To(Succeed())
+
+		// Events of equal time may be interleaved differently, but the
+		// order of each node's events, and by time, is retained.
+		original := readAll(bytes.NewReader(logBytes.Bytes()))
+		events := readAll(bytes.NewReader(merged.Bytes()))
+		Expect(events).To(HaveLen(len(original)))
+
+		perNode := func(events []*recording.Event) map[uint64][]*recording.Event {
+			result := map[uint64][]*recording.Event{}
+			for _, event := range events {
+				result[event.NodeId] = append(result[event.NodeId], event)
+			}
+			return result
+		}
+		originalPerNode := perNode(original)
+		for nodeID, nodeEvents := range perNode(events) {
+			for i, event := range nodeEvents {
+				Expect(proto.Equal(event, originalPerNode[nodeID][i])).To(BeTrue())
+			}
+		}
+
+		for i := 1; i < len(events); i++ {
+			Expect(events[i].Time).To(BeNumerically(">=", events[i-1].Time))
+		}
+
+		Expect((&arguments{
+			input:       ioutil.NopCloser(bytes.NewReader(merged.Bytes())),
+			interactive: true,
+			logLevel:    statemachine.LevelError,
+			eventTypes:  []string{},
+		}).execute(&bytes.Buffer{})).To(Succeed())
+	})
+
+	When("the split files exist", func() {
+		It("returns an error", func() {
+			Expect(ioutil.WriteFile(filepath.Join(tmpDir, splitFileName(0)), nil, 0600)).To(Succeed())
+			Expect(split()).To(MatchError(MatchRegexp(`could not create destination for node 0: open .*/node0.eventlog.gz: file exists`)))
+		})
+	})
+})
+
+type readSeekCloser struct {
+	*bytes.Reader
+}
+
+func (readSeekCloser) Close() error {
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+)
+
+// merge writes the recordings named by the merge inputs to the output as a
+// single recording, ordered by time.
+func (a *arguments) merge(output io.Writer) error {
+	readers := make([]*eventlog.Reader, len(a.mergeInputs))
+	for i, path := range a.mergeInputs {
+		file, err := os.Open(path)
+		if err != nil {
+			return errors.WithMessage(err, "could not open input file")
+		}
+		defer file.Close()
+
+		readers[i], err = eventlog.NewReader(file)
+		if err != nil {
+			return errors.WithMessagef(err, "bad input file %s", path)
+		}
+	}
+
+	return eventlog.Merge(output, readers...)
+}
+
+// splitFileName returns the name of the file holding a node's events once
+// split from a recording.
+func splitFileName(nodeID uint64) string {
+	return fmt.Sprintf("node%d.eventlog.gz", nodeID)
+}
+
+// split writes the events of each node in the input to a recording of its
+// own in the split output directory, refusing to overwrite existing files.
+func (a *arguments) split(output io.Writer) error {
+	reader, err := eventlog.NewReader(a.input)
+	if err != nil {
+		return errors.WithMessage(err, "bad input file")
+	}
+
+	if err := os.MkdirAll(a.splitOutput, 0700); err != nil {
+		return errors.WithMessagef(err, "could not create %s", a.splitOutput)
+	}
+
+	var files []*os.File
+	splitErr := eventlog.Split(reader, func(nodeID uint64) (io.Writer, error) {
+		file, err := os.OpenFile(filepath.Join(a.splitOutput, splitFileName(nodeID)), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+		return file, nil
+	})
+
+	for _, file := range files {
+		if err := file.Close(); err != nil && splitErr == nil {
+			splitErr = errors.WithMessagef(err, "could not close %s", file.Name())
+		}
+	}
+
+	if splitErr != nil {
+		return splitErr
+	}
+
+	for _, file := range files {
+		fmt.Fprintf(output, "Wrote %s\n", file.Name())
+	}
+
+	return nil
+}
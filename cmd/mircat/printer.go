@@ -0,0 +1,199 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+const (
+	formatText  = "text"
+	formatJSON  = "json"
+	formatJSONL = "jsonl"
+)
+
+var allFormats = []string{formatText, formatJSON, formatJSONL}
+
+// printer writes the events, and in interactive mode the resulting actions
+// and statuses, selected for output.
+type printer interface {
+	event(index uint64, event *recording.Event) error
+	actions(index uint64, event *recording.Event, actions *statemachine.ActionList) error
+	status(index uint64, event *recording.Event, status *status.StateMachine) error
+
+	// flush is invoked once all output for an event has been supplied.
+	flush() error
+
+	// close is invoked once all events have been read.
+	close() error
+}
+
+func newPrinter(format string, output io.Writer, verboseText bool) printer {
+	switch format {
+	case formatJSON:
+		return &jsonPrinter{output: output, array: true}
+	case formatJSONL:
+		return &jsonPrinter{output: output}
+	default:
+		return &textPrinter{output: output, shortBytes: !verboseText}
+	}
+}
+
+type textPrinter struct {
+	output     io.Writer
+	shortBytes bool
+}
+
+func (tp *textPrinter) event(index uint64, event *recording.Event) error {
+	text, err := textFormat(withoutActions(event), tp.shortBytes)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal event")
+	}
+
+	fmt.Fprintf(tp.output, "% 6d %s\n", index, text)
+	return nil
+}
+
+func (tp *textPrinter) actions(index uint64, event *recording.Event, actions *statemachine.ActionList) error {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		text, err := textFormat(action, tp.shortBytes)
+		if err != nil {
+			return errors.WithMessage(err, "could not marshal actions")
+		}
+		fmt.Fprintf(tp.output, "       actions: %s\n", text)
+	}
+	return nil
+}
+
+func (tp *textPrinter) status(index uint64, event *recording.Event, status *status.StateMachine) error {
+	fmt.Fprint(tp.output, status.Pretty())
+	fmt.Fprint(tp.output, "\n")
+	return nil
+}
+
+func (tp *textPrinter) flush() error {
+	return nil
+}
+
+func (tp *textPrinter) close() error {
+	return nil
+}
+
+// jsonRecord is the JSON representation of an event and the output
+// resulting from it.  Protos are encoded via protojson using the proto
+// field names.
+type jsonRecord struct {
+	Index   uint64               `json:"index"`
+	NodeID  uint64               `json:"node_id"`
+	Event   json.RawMessage      `json:"event,omitempty"`
+	Actions []json.RawMessage    `json:"actions,omitempty"`
+	Status  *status.StateMachine `json:"status,omitempty"`
+}
+
+var protoJSON = protojson.MarshalOptions{UseProtoNames: true}
+
+// jsonPrinter writes a JSON record per event, either one per line, or as
+// the elements of a single JSON array.
+type jsonPrinter struct {
+	output  io.Writer
+	array   bool
+	written int
+	record  *jsonRecord
+}
+
+func (jp *jsonPrinter) current(index uint64, event *recording.Event) *jsonRecord {
+	if jp.record == nil {
+		jp.record = &jsonRecord{
+			Index:  index,
+			NodeID: event.NodeId,
+		}
+	}
+	return jp.record
+}
+
+func (jp *jsonPrinter) event(index uint64, event *recording.Event) error {
+	eventJSON, err := protoJSON.Marshal(event)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal event")
+	}
+
+	jp.current(index, event).Event = eventJSON
+	return nil
+}
+
+func (jp *jsonPrinter) actions(index uint64, event *recording.Event, actions *statemachine.ActionList) error {
+	record := jp.current(index, event)
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		actionJSON, err := protoJSON.Marshal(action)
+		if err != nil {
+			return errors.WithMessage(err, "could not marshal actions")
+		}
+		record.Actions = append(record.Actions, actionJSON)
+	}
+	return nil
+}
+
+func (jp *jsonPrinter) status(index uint64, event *recording.Event, status *status.StateMachine) error {
+	jp.current(index, event).Status = status
+	return nil
+}
+
+func (jp *jsonPrinter) flush() error {
+	if jp.record == nil {
+		return nil
+	}
+
+	record := jp.record
+	jp.record = nil
+	return jp.writeValue(record)
+}
+
+// writeValue writes the JSON encoding of value as the next line or array element.
+func (jp *jsonPrinter) writeValue(value interface{}) error {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal record")
+	}
+
+	switch {
+	case !jp.array:
+	case jp.written == 0:
+		fmt.Fprint(jp.output, "[\n")
+	default:
+		fmt.Fprint(jp.output, ",\n")
+	}
+	jp.written++
+
+	_, err = jp.output.Write(valueJSON)
+	if err == nil && !jp.array {
+		_, err = fmt.Fprint(jp.output, "\n")
+	}
+	return err
+}
+
+func (jp *jsonPrinter) close() error {
+	if !jp.array {
+		return nil
+	}
+
+	if jp.written == 0 {
+		fmt.Fprint(jp.output, "[")
+	}
+	_, err := fmt.Fprint(jp.output, "\n]\n")
+	return err
+}
@@ -0,0 +1,364 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+// stats summarizes a recording.  All times are in recording time.
+type stats struct {
+	Events         int                          `json:"events"`
+	StartTime      int64                        `json:"start_time"`
+	EndTime        int64                        `json:"end_time"`
+	Nodes          []*nodeStats                 `json:"nodes"`
+	MessageTypes   map[string]*messageTypeStats `json:"message_types"`
+	CommitLatency  *latencyStats                `json:"commit_latency"`
+	StateTransfers []*stateTransferStats        `json:"state_transfers"`
+
+	nodes     map[uint64]*nodeStats
+	firstAcks map[clientReq]int64
+	latencies []int64
+}
+
+type nodeStats struct {
+	NodeID      uint64             `json:"node_id"`
+	Events      map[string]int     `json:"events"`
+	Messages    map[string]int     `json:"messages"`
+	Commits     int                `json:"commits"`
+	Epochs      []*epochStats      `json:"epochs"`
+	Checkpoints []*checkpointStats `json:"checkpoints"`
+
+	inProgress  bool
+	epoch       uint64
+	changeStart int64
+}
+
+// epochStats records when an epoch became active at a node, and how long
+// the node spent changing to it, from leaving its previous active epoch (or
+// from initializing).
+type epochStats struct {
+	Number         uint64 `json:"number"`
+	ActiveAt       int64  `json:"active_at"`
+	ChangeDuration int64  `json:"change_duration"`
+}
+
+type checkpointStats struct {
+	SeqNo    uint64 `json:"seq_no"`
+	StableAt int64  `json:"stable_at"`
+}
+
+type messageTypeStats struct {
+	Count int `json:"count"`
+	Bytes int `json:"bytes"`
+}
+
+// latencyStats describes the latency from the first RequestAck for a
+// request in the recording to its commit, for each commit at each node.
+type latencyStats struct {
+	Count int   `json:"count"`
+	Min   int64 `json:"min"`
+	Mean  int64 `json:"mean"`
+	P50   int64 `json:"p50"`
+	P99   int64 `json:"p99"`
+	Max   int64 `json:"max"`
+}
+
+type stateTransferStats struct {
+	Index     uint64 `json:"index"`
+	NodeID    uint64 `json:"node_id"`
+	Time      int64  `json:"time"`
+	SeqNo     uint64 `json:"seq_no"`
+	Completed bool   `json:"completed"`
+}
+
+type clientReq struct {
+	clientID uint64
+	reqNo    uint64
+}
+
+func newStats() *stats {
+	return &stats{
+		MessageTypes: map[string]*messageTypeStats{},
+		nodes:        map[uint64]*nodeStats{},
+		firstAcks:    map[clientReq]int64{},
+	}
+}
+
+func (s *stats) node(event *recording.Event) *nodeStats {
+	ns, ok := s.nodes[event.NodeId]
+	if !ok {
+		ns = &nodeStats{
+			NodeID:      event.NodeId,
+			Events:      map[string]int{},
+			Messages:    map[string]int{},
+			changeStart: event.Time,
+		}
+		s.nodes[event.NodeId] = ns
+		s.Nodes = append(s.Nodes, ns)
+	}
+	return ns
+}
+
+func (s *stats) ack(ack *msgs.RequestAck, time int64) {
+	key := clientReq{clientID: ack.ClientId, reqNo: ack.ReqNo}
+	if _, ok := s.firstAcks[key]; !ok {
+		s.firstAcks[key] = time
+	}
+}
+
+// observe accounts for an event, the actions its application produced, and
+// the resulting facets of the node's state machine.
+func (s *stats) observe(index uint64, event *recording.Event, actions *statemachine.ActionList, facets *status.Facets) {
+	if s.Events == 0 {
+		s.StartTime = event.Time
+	}
+	s.Events++
+	s.EndTime = event.Time
+
+	ns := s.node(event)
+	ns.Events[eventTypeName(event.StateEvent)]++
+
+	switch et := event.StateEvent.Type.(type) {
+	case *state.Event_Initialize:
+		ns.inProgress = false
+		ns.changeStart = event.Time
+	case *state.Event_RequestPersisted:
+		s.ack(et.RequestPersisted.RequestAck, event.Time)
+	case *state.Event_Step:
+		msgType := msgTypeName(et.Step.Msg)
+		ns.Messages[msgType]++
+
+		mts, ok := s.MessageTypes[msgType]
+		if !ok {
+			mts = &messageTypeStats{}
+			s.MessageTypes[msgType] = mts
+		}
+		mts.Count++
+		mts.Bytes += proto.Size(et.Step.Msg)
+
+		if ack, ok := et.Step.Msg.Type.(*msgs.Msg_RequestAck); ok {
+			s.ack(ack.RequestAck, event.Time)
+		}
+	case *state.Event_StateTransferComplete:
+		s.StateTransfers = append(s.StateTransfers, &stateTransferStats{
+			Index:     index,
+			NodeID:    event.NodeId,
+			Time:      event.Time,
+			SeqNo:     et.StateTransferComplete.SeqNo,
+			Completed: true,
+		})
+	case *state.Event_StateTransferFailed:
+		s.StateTransfers = append(s.StateTransfers, &stateTransferStats{
+			Index:  index,
+			NodeID: event.NodeId,
+			Time:   event.Time,
+			SeqNo:  et.StateTransferFailed.SeqNo,
+		})
+	}
+
+	// The actions for ActionsReceived events were already observed as
+	// produced by the preceding events.
+	if _, ok := event.StateEvent.Type.(*state.Event_ActionsReceived); !ok {
+		iter := actions.Iterator()
+		for action := iter.Next(); action != nil; action = iter.Next() {
+			commit, ok := action.Type.(*state.Action_Commit)
+			if !ok {
+				continue
+			}
+
+			ns.Commits++
+			for _, ack := range commit.Commit.Batch.Requests {
+				if ackTime, ok := s.firstAcks[clientReq{clientID: ack.ClientId, reqNo: ack.ReqNo}]; ok {
+					s.latencies = append(s.latencies, event.Time-ackTime)
+				}
+			}
+		}
+	}
+
+	switch {
+	case facets.EpochState == status.EpochInProgress && (!ns.inProgress || facets.EpochNumber != ns.epoch):
+		ns.Epochs = append(ns.Epochs, &epochStats{
+			Number:         facets.EpochNumber,
+			ActiveAt:       event.Time,
+			ChangeDuration: event.Time - ns.changeStart,
+		})
+		ns.inProgress = true
+		ns.epoch = facets.EpochNumber
+	case facets.EpochState != status.EpochInProgress && ns.inProgress:
+		ns.inProgress = false
+		ns.changeStart = event.Time
+	}
+
+	if l := len(ns.Checkpoints); l == 0 || facets.StableCheckpoint > ns.Checkpoints[l-1].SeqNo {
+		ns.Checkpoints = append(ns.Checkpoints, &checkpointStats{
+			SeqNo:    facets.StableCheckpoint,
+			StableAt: event.Time,
+		})
+	}
+}
+
+func (s *stats) finish() {
+	sort.Slice(s.Nodes, func(i, j int) bool {
+		return s.Nodes[i].NodeID < s.Nodes[j].NodeID
+	})
+
+	if len(s.latencies) == 0 {
+		return
+	}
+
+	sort.Slice(s.latencies, func(i, j int) bool {
+		return s.latencies[i] < s.latencies[j]
+	})
+
+	var total int64
+	for _, latency := range s.latencies {
+		total += latency
+	}
+
+	percentile := func(p int) int64 {
+		return s.latencies[(len(s.latencies)-1)*p/100]
+	}
+
+	s.CommitLatency = &latencyStats{
+		Count: len(s.latencies),
+		Min:   s.latencies[0],
+		Mean:  total / int64(len(s.latencies)),
+		P50:   percentile(50),
+		P99:   percentile(99),
+		Max:   s.latencies[len(s.latencies)-1],
+	}
+}
+
+// collectStats replays the recording, summarizing it.
+func collectStats(input io.Reader, logOutput io.Writer, logLevel statemachine.LogLevel) (*stats, error) {
+	r, err := newReplay(input, logOutput, logLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	s := newStats()
+	for r.next() {
+		s.observe(r.index, r.event, r.actions, r.facets())
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	s.finish()
+
+	return s, nil
+}
+
+func sortedCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%d", name, counts[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+func (s *stats) writeText(output io.Writer) {
+	fmt.Fprintf(output, "Recording: %d events from %d nodes, time %d to %d\n", s.Events, len(s.Nodes), s.StartTime, s.EndTime)
+
+	fmt.Fprintf(output, "\nEvents by type:\n")
+	for _, ns := range s.Nodes {
+		fmt.Fprintf(output, "  node %d: %s\n", ns.NodeID, sortedCounts(ns.Events))
+	}
+
+	fmt.Fprintf(output, "\nMessages received by type:\n")
+	for _, ns := range s.Nodes {
+		fmt.Fprintf(output, "  node %d: %s\n", ns.NodeID, sortedCounts(ns.Messages))
+	}
+
+	fmt.Fprintf(output, "\nMessage bytes by type:\n")
+	msgTypes := make([]string, 0, len(s.MessageTypes))
+	for msgType := range s.MessageTypes {
+		msgTypes = append(msgTypes, msgType)
+	}
+	sort.Strings(msgTypes)
+	for _, msgType := range msgTypes {
+		mts := s.MessageTypes[msgType]
+		fmt.Fprintf(output, "  %-16s count=%-8d bytes=%d\n", msgType, mts.Count, mts.Bytes)
+	}
+
+	fmt.Fprintf(output, "\nEpochs:\n")
+	for _, ns := range s.Nodes {
+		epochs := make([]string, len(ns.Epochs))
+		for i, epoch := range ns.Epochs {
+			epochs[i] = fmt.Sprintf("%d@%d(change=%d)", epoch.Number, epoch.ActiveAt, epoch.ChangeDuration)
+		}
+		fmt.Fprintf(output, "  node %d: %s\n", ns.NodeID, strings.Join(epochs, " "))
+	}
+
+	fmt.Fprintf(output, "\nStable checkpoints:\n")
+	for _, ns := range s.Nodes {
+		checkpoints := make([]string, len(ns.Checkpoints))
+		for i, checkpoint := range ns.Checkpoints {
+			checkpoints[i] = fmt.Sprintf("%d@%d", checkpoint.SeqNo, checkpoint.StableAt)
+		}
+		fmt.Fprintf(output, "  node %d: %s", ns.NodeID, strings.Join(checkpoints, " "))
+		if l := len(ns.Checkpoints); l > 1 {
+			first, last := ns.Checkpoints[0], ns.Checkpoints[l-1]
+			fmt.Fprintf(output, " (mean spacing %d seq_nos, %d time)",
+				(last.SeqNo-first.SeqNo)/uint64(l-1), (last.StableAt-first.StableAt)/int64(l-1))
+		}
+		fmt.Fprintf(output, "\n")
+	}
+
+	fmt.Fprintf(output, "\nCommits:\n")
+	for _, ns := range s.Nodes {
+		fmt.Fprintf(output, "  node %d: %d\n", ns.NodeID, ns.Commits)
+	}
+
+	fmt.Fprintf(output, "\nCommit latency (first RequestAck to commit):\n")
+	if cl := s.CommitLatency; cl != nil {
+		fmt.Fprintf(output, "  count=%d min=%d mean=%d p50=%d p99=%d max=%d\n", cl.Count, cl.Min, cl.Mean, cl.P50, cl.P99, cl.Max)
+	} else {
+		fmt.Fprintf(output, "  none\n")
+	}
+
+	fmt.Fprintf(output, "\nState transfers:\n")
+	if len(s.StateTransfers) == 0 {
+		fmt.Fprintf(output, "  none\n")
+	}
+	for _, st := range s.StateTransfers {
+		result := "failed"
+		if st.Completed {
+			result = "completed"
+		}
+		fmt.Fprintf(output, "  % 6d node %d time=%d seq_no=%d %s\n", st.Index, st.NodeID, st.Time, st.SeqNo, result)
+	}
+}
+
+func (s *stats) writeJSON(output io.Writer) error {
+	statsJSON, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal stats")
+	}
+	_, err = fmt.Fprintf(output, "%s\n", statsJSON)
+	return err
+}
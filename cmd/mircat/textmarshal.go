@@ -60,6 +60,13 @@ func (te *textEncoder) marshalMessage(m pref.Message) error {
 			i++
 		}
 
+		// Skip unset message fields, and the unset fields of recorded
+		// events, such as the optional recorded actions and request data.
+		if fd != nil && !m.Has(fd) && (fd.Kind() == pref.MessageKind && !fd.IsList() && !fd.IsMap() ||
+			fd.Kind() == pref.BytesKind && messageDesc.ParentFile().Package() == "recording") {
+			continue
+		}
+
 		name := fd.Name()
 		// Use type name for group field name.
 		if fd.Kind() == pref.GroupKind {
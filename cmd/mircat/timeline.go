@@ -0,0 +1,326 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+// The threads of each node's process in the exported timeline.
+const (
+	timelineEventsTid = iota
+	timelineEpochsTid
+	timelineWindowsTid
+)
+
+var timelineThreadNames = []string{"events", "epochs", "checkpoint windows"}
+
+// chromeEvent is an entry of the Chrome trace-event format, see
+// https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type chromeEvent struct {
+	Name string                 `json:"name"`
+	Cat  string                 `json:"cat,omitempty"`
+	Ph   string                 `json:"ph"`
+	Ts   int64                  `json:"ts"`
+	Dur  *int64                 `json:"dur,omitempty"`
+	Pid  uint64                 `json:"pid"`
+	Tid  int                    `json:"tid"`
+	ID   uint64                 `json:"id,omitempty"`
+	Bp   string                 `json:"bp,omitempty"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+type chromeTrace struct {
+	TraceEvents     []*chromeEvent `json:"traceEvents"`
+	DisplayTimeUnit string         `json:"displayTimeUnit"`
+}
+
+// nodeTimeline holds the spans of a node which are still open.
+type nodeTimeline struct {
+	lastTime int64
+	event    *chromeEvent
+	epoch    *chromeEvent
+	window   *chromeEvent
+	facets   status.Facets
+}
+
+// pendingFlow is a sent message which has not yet been stepped by its target.
+type pendingFlow struct {
+	nodeID uint64
+	ts     int64
+}
+
+// timeline converts a replayed recording into Chrome trace events.  Each
+// node is a process with threads for its events, epochs, and checkpoint
+// windows, and each delivered message is a flow from the event whose
+// actions sent it, to the event stepping it.  Recordings carry no
+// processing durations, so each event spans until the next event of its
+// node.  Recording times are taken to be milliseconds.
+type timeline struct {
+	shortBytes bool
+	events     []*chromeEvent
+	nodes      map[uint64]*nodeTimeline
+	sends      map[string][]*pendingFlow
+	nextFlowID uint64
+}
+
+func newTimeline(shortBytes bool) *timeline {
+	return &timeline{
+		shortBytes: shortBytes,
+		nodes:      map[uint64]*nodeTimeline{},
+		sends:      map[string][]*pendingFlow{},
+	}
+}
+
+func timelineTs(time int64) int64 {
+	return time * 1000
+}
+
+func flowKey(source, target uint64, msg *msgs.Msg) (string, error) {
+	msgBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return "", errors.WithMessage(err, "could not marshal message")
+	}
+	return fmt.Sprintf("%d-%d-%s", source, target, msgBytes), nil
+}
+
+func (t *timeline) open(nodeID uint64, tid int, time int64, name string, args map[string]interface{}) *chromeEvent {
+	span := &chromeEvent{
+		Name: name,
+		Ph:   "X",
+		Ts:   timelineTs(time),
+		Pid:  nodeID,
+		Tid:  tid,
+		Args: args,
+	}
+	t.events = append(t.events, span)
+	return span
+}
+
+func closeSpan(span *chromeEvent, time int64) {
+	if span == nil {
+		return
+	}
+	dur := timelineTs(time) - span.Ts
+	span.Dur = &dur
+}
+
+func (t *timeline) node(nodeID uint64) *nodeTimeline {
+	nt, ok := t.nodes[nodeID]
+	if !ok {
+		nt = &nodeTimeline{}
+		t.nodes[nodeID] = nt
+
+		t.events = append(t.events,
+			&chromeEvent{
+				Name: "process_name",
+				Ph:   "M",
+				Pid:  nodeID,
+				Args: map[string]interface{}{"name": fmt.Sprintf("node %d", nodeID)},
+			},
+			&chromeEvent{
+				Name: "process_sort_index",
+				Ph:   "M",
+				Pid:  nodeID,
+				Args: map[string]interface{}{"sort_index": nodeID},
+			},
+		)
+		for tid, name := range timelineThreadNames {
+			t.events = append(t.events, &chromeEvent{
+				Name: "thread_name",
+				Ph:   "M",
+				Pid:  nodeID,
+				Tid:  tid,
+				Args: map[string]interface{}{"name": name},
+			})
+		}
+	}
+	return nt
+}
+
+// observe adds an event, the actions its application produced, and the
+// resulting facets of the node's state machine to the timeline.
+func (t *timeline) observe(index uint64, event *recording.Event, actions *statemachine.ActionList, facets *status.Facets) error {
+	nt := t.node(event.NodeId)
+	nt.lastTime = event.Time
+
+	text, err := textFormat(event.StateEvent, t.shortBytes)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal event")
+	}
+
+	name := eventTypeName(event.StateEvent)
+	if step, ok := event.StateEvent.Type.(*state.Event_Step); ok {
+		name = fmt.Sprintf("%s %s", name, msgTypeName(step.Step.Msg))
+	}
+
+	closeSpan(nt.event, event.Time)
+	nt.event = t.open(event.NodeId, timelineEventsTid, event.Time, name, map[string]interface{}{
+		"index": index,
+		"event": text,
+	})
+	nt.event.Cat = "event"
+
+	switch et := event.StateEvent.Type.(type) {
+	case *state.Event_Initialize:
+		// The node restarted, its previous epoch and window are over.
+		closeSpan(nt.epoch, event.Time)
+		closeSpan(nt.window, event.Time)
+		nt.epoch, nt.window, nt.facets = nil, nil, status.Facets{}
+	case *state.Event_Step:
+		if err := t.receive(event, et.Step); err != nil {
+			return err
+		}
+	case *state.Event_ActionsReceived:
+		// The actions for ActionsReceived events were already observed as
+		// produced by the preceding events.
+		actions = &statemachine.ActionList{}
+	}
+
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		send, ok := action.Type.(*state.Action_Send)
+		if !ok {
+			continue
+		}
+
+		for _, target := range send.Send.Targets {
+			key, err := flowKey(event.NodeId, target, send.Send.Msg)
+			if err != nil {
+				return err
+			}
+			t.sends[key] = append(t.sends[key], &pendingFlow{
+				nodeID: event.NodeId,
+				ts:     timelineTs(event.Time),
+			})
+		}
+	}
+
+	t.observeFacets(event, nt, facets)
+
+	return nil
+}
+
+// receive adds a flow for the stepped message, if the send which caused it
+// was recorded.
+func (t *timeline) receive(event *recording.Event, step *state.EventStep) error {
+	key, err := flowKey(step.Source, event.NodeId, step.Msg)
+	if err != nil {
+		return err
+	}
+
+	pending := t.sends[key]
+	if len(pending) == 0 {
+		return nil
+	}
+	send := pending[0]
+	if len(pending) == 1 {
+		delete(t.sends, key)
+	} else {
+		t.sends[key] = pending[1:]
+	}
+
+	t.nextFlowID++
+	name := msgTypeName(step.Msg)
+	t.events = append(t.events,
+		&chromeEvent{
+			Name: name,
+			Cat:  "message",
+			Ph:   "s",
+			Ts:   send.ts,
+			Pid:  send.nodeID,
+			Tid:  timelineEventsTid,
+			ID:   t.nextFlowID,
+		},
+		&chromeEvent{
+			Name: name,
+			Cat:  "message",
+			Ph:   "f",
+			Bp:   "e",
+			Ts:   timelineTs(event.Time),
+			Pid:  event.NodeId,
+			Tid:  timelineEventsTid,
+			ID:   t.nextFlowID,
+		},
+	)
+
+	return nil
+}
+
+// observeFacets starts new epoch and checkpoint window spans as the facets
+// change.  Until the state machine completes initialization it has no
+// facets, and so no spans are open.
+func (t *timeline) observeFacets(event *recording.Event, nt *nodeTimeline, facets *status.Facets) {
+	if *facets == (status.Facets{}) {
+		return
+	}
+
+	if nt.epoch == nil || facets.EpochNumber != nt.facets.EpochNumber || facets.EpochState != nt.facets.EpochState {
+		closeSpan(nt.epoch, event.Time)
+		nt.epoch = t.open(event.NodeId, timelineEpochsTid, event.Time, fmt.Sprintf("epoch %d %s", facets.EpochNumber, facets.EpochState), nil)
+		nt.epoch.Cat = "epoch"
+	}
+
+	if nt.window == nil || facets.LowWatermark != nt.facets.LowWatermark || facets.HighWatermark != nt.facets.HighWatermark {
+		closeSpan(nt.window, event.Time)
+		nt.window = t.open(event.NodeId, timelineWindowsTid, event.Time, fmt.Sprintf("window %d-%d", facets.LowWatermark, facets.HighWatermark), map[string]interface{}{
+			"stable_checkpoint": facets.StableCheckpoint,
+		})
+		nt.window.Cat = "checkpoint"
+	}
+
+	nt.facets = *facets
+}
+
+// finish closes the spans still open at the last event of each node.
+func (t *timeline) finish() *chromeTrace {
+	for _, nt := range t.nodes {
+		for _, span := range []*chromeEvent{nt.event, nt.epoch, nt.window} {
+			closeSpan(span, nt.lastTime)
+		}
+	}
+
+	return &chromeTrace{
+		TraceEvents:     t.events,
+		DisplayTimeUnit: "ms",
+	}
+}
+
+// collectTimeline replays the recording, converting it to a Chrome trace.
+func collectTimeline(input io.Reader, logOutput io.Writer, logLevel statemachine.LogLevel, shortBytes bool) (*chromeTrace, error) {
+	r, err := newReplay(input, logOutput, logLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	t := newTimeline(shortBytes)
+	for r.next() {
+		if err := t.observe(r.index, r.event, r.actions, r.facets()); err != nil {
+			return nil, err
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return t.finish(), nil
+}
+
+func (ct *chromeTrace) write(output io.Writer) error {
+	return errors.WithMessage(json.NewEncoder(output).Encode(ct), "could not write trace")
+}
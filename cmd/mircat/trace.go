@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	pref "google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// traceTarget selects either a sequence number, or a client request.
+type traceTarget struct {
+	seqNo    *uint64
+	clientID *uint64
+	reqNo    *uint64
+}
+
+func (tt *traceTarget) String() string {
+	if tt.seqNo != nil {
+		return fmt.Sprintf("seq_no %d", *tt.seqNo)
+	}
+	return fmt.Sprintf("client %d req_no %d", *tt.clientID, *tt.reqNo)
+}
+
+// matches returns whether the message, or any message nested within it,
+// refers to the target.
+func (tt *traceTarget) matches(m pref.Message) bool {
+	fields := m.Descriptor().Fields()
+
+	getUint := func(name pref.Name) (uint64, bool) {
+		fd := fields.ByName(name)
+		if fd == nil || fd.Kind() != pref.Uint64Kind || fd.IsList() {
+			return 0, false
+		}
+		return m.Get(fd).Uint(), true
+	}
+
+	if tt.seqNo != nil {
+		if seqNo, ok := getUint("seq_no"); ok && seqNo == *tt.seqNo {
+			return true
+		}
+	} else {
+		clientID, hasClientID := getUint("client_id")
+		reqNo, hasReqNo := getUint("req_no")
+		if hasClientID && hasReqNo && clientID == *tt.clientID && reqNo == *tt.reqNo {
+			return true
+		}
+	}
+
+	found := false
+	m.Range(func(fd pref.FieldDescriptor, v pref.Value) bool {
+		if fd.Kind() != pref.MessageKind || fd.IsMap() {
+			return true
+		}
+
+		if fd.IsList() {
+			list := v.List()
+			for i := 0; i < list.Len() && !found; i++ {
+				found = tt.matches(list.Get(i).Message())
+			}
+		} else {
+			found = tt.matches(v.Message())
+		}
+
+		return !found
+	})
+
+	return found
+}
+
+// traceEntry is an event applied to a node, or an action the node produced,
+// which refers to the trace target.
+type traceEntry struct {
+	Index  uint64          `json:"index"`
+	NodeID uint64          `json:"node_id"`
+	Time   int64           `json:"time"`
+	Kind   string          `json:"kind"`
+	Item   json.RawMessage `json:"item"`
+
+	item proto.Message
+}
+
+// collectTrace replays the recording, returning the entries referring to the
+// target, grouped by node and ordered by time.
+func collectTrace(input io.Reader, logOutput io.Writer, logLevel statemachine.LogLevel, target *traceTarget) (map[uint64][]*traceEntry, error) {
+	r, err := newReplay(input, logOutput, logLevel)
+	if err != nil {
+		return nil, err
+	}
+
+	timelines := map[uint64][]*traceEntry{}
+	for r.next() {
+		event := r.event
+
+		add := func(kind string, item proto.Message) {
+			timelines[event.NodeId] = append(timelines[event.NodeId], &traceEntry{
+				Index:  r.index,
+				NodeID: event.NodeId,
+				Time:   event.Time,
+				Kind:   kind,
+				item:   item,
+			})
+		}
+
+		if target.matches(event.StateEvent.ProtoReflect()) {
+			add("event", event.StateEvent)
+		}
+
+		// The actions for ActionsReceived events were already traced as
+		// produced by the preceding events.
+		if _, ok := event.StateEvent.Type.(*state.Event_ActionsReceived); ok {
+			continue
+		}
+
+		iter := r.actions.Iterator()
+		for action := iter.Next(); action != nil; action = iter.Next() {
+			if target.matches(action.ProtoReflect()) {
+				add("action", action)
+			}
+		}
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	for _, timeline := range timelines {
+		sort.SliceStable(timeline, func(i, j int) bool {
+			return timeline[i].Time < timeline[j].Time
+		})
+	}
+
+	return timelines, nil
+}
+
+func sortedNodeIDs(timelines map[uint64][]*traceEntry) []uint64 {
+	nodeIDs := make([]uint64, 0, len(timelines))
+	for nodeID := range timelines {
+		nodeIDs = append(nodeIDs, nodeID)
+	}
+	sort.Slice(nodeIDs, func(i, j int) bool {
+		return nodeIDs[i] < nodeIDs[j]
+	})
+	return nodeIDs
+}
+
+func writeTraceText(output io.Writer, target *traceTarget, timelines map[uint64][]*traceEntry, shortBytes bool) error {
+	fmt.Fprintf(output, "Trace of %s across %d nodes\n", target, len(timelines))
+
+	for _, nodeID := range sortedNodeIDs(timelines) {
+		fmt.Fprintf(output, "\nnode %d:\n", nodeID)
+		for _, entry := range timelines[nodeID] {
+			text, err := textFormat(entry.item, shortBytes)
+			if err != nil {
+				return errors.WithMessage(err, "could not marshal trace entry")
+			}
+			fmt.Fprintf(output, "  time=%-8d index=%-8d %-6s %s\n", entry.Time, entry.Index, entry.Kind, text)
+		}
+	}
+
+	return nil
+}
+
+func writeTraceJSON(output io.Writer, timelines map[uint64][]*traceEntry, format string) error {
+	p := &jsonPrinter{output: output, array: format == formatJSON}
+	for _, nodeID := range sortedNodeIDs(timelines) {
+		for _, entry := range timelines[nodeID] {
+			itemJSON, err := protoJSON.Marshal(entry.item)
+			if err != nil {
+				return errors.WithMessage(err, "could not marshal trace entry")
+			}
+			entry.Item = itemJSON
+
+			if err := p.writeValue(entry); err != nil {
+				return err
+			}
+		}
+	}
+
+	return p.close()
+}
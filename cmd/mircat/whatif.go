@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	_ "crypto/sha256" // Registers the hash used by the simulation.
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+// initOverrides replace the recorded initial parameters of Initialize
+// events, each is nil unless set.
+type initOverrides struct {
+	batchSize            *uint32
+	heartbeatTicks       *uint32
+	suspectTicks         *uint32
+	newEpochTimeoutTicks *uint32
+	bufferSize           *uint32
+}
+
+func (o *initOverrides) set() bool {
+	return o.batchSize != nil ||
+		o.heartbeatTicks != nil ||
+		o.suspectTicks != nil ||
+		o.newEpochTimeoutTicks != nil ||
+		o.bufferSize != nil
+}
+
+// apply returns the event with its initial parameters overridden, if it is
+// an Initialize event, and otherwise the event itself.
+func (o *initOverrides) apply(event *recording.Event) *recording.Event {
+	initialize, ok := event.StateEvent.Type.(*state.Event_Initialize)
+	if !ok {
+		return event
+	}
+
+	initParms := proto.Clone(initialize.Initialize).(*state.EventInitialParameters)
+	for _, override := range []struct {
+		value *uint32
+		field *uint32
+	}{
+		{o.batchSize, &initParms.BatchSize},
+		{o.heartbeatTicks, &initParms.HeartbeatTicks},
+		{o.suspectTicks, &initParms.SuspectTicks},
+		{o.newEpochTimeoutTicks, &initParms.NewEpochTimeoutTicks},
+		{o.bufferSize, &initParms.BufferSize},
+	} {
+		if override.value != nil {
+			*override.field = *override.value
+		}
+	}
+
+	return &recording.Event{
+		NodeId: event.NodeId,
+		Time:   event.Time,
+		StateEvent: &state.Event{
+			Type: &state.Event_Initialize{
+				Initialize: initParms,
+			},
+		},
+	}
+}
+
+// whatIf tracks a replay with overridden initial parameters against
+// reference state machines replaying the recording verbatim.  Once the
+// replayed actions diverge from the reference ones, the rest of the
+// recording no longer applies, and the replay continues in the testengine
+// instead, from the state the continuation reconstructed.
+type whatIf struct {
+	overrides    *initOverrides
+	reference    *stateMachines
+	continuation *testengine.Continuation
+}
+
+func newWhatIf(overrides *initOverrides, logLevel statemachine.LogLevel) *whatIf {
+	return &whatIf{
+		overrides:    overrides,
+		reference:    newStateMachines(ioutil.Discard, logLevel),
+		continuation: testengine.NewContinuation(crypto.SHA256),
+	}
+}
+
+// observe applies the recorded event to the reference state machines,
+// returning the positions at which the actions replayed for the overridden
+// event differ from the reference ones.
+func (w *whatIf) observe(event, overridden *recording.Event, actions *statemachine.ActionList) ([]*actionDiff, error) {
+	reference, err := w.reference.apply(event)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not apply event to reference state machine")
+	}
+
+	if err := w.continuation.Observe(overridden, actions); err != nil {
+		return nil, errors.WithMessage(err, "could not follow recording for simulation")
+	}
+
+	return diffActions(eventlog.RecordedActions(reference), actions), nil
+}
+
+// simulate continues the replay in the testengine until the clients drain
+// or the step limit is reached, printing the simulated events with indices
+// following the given one.
+func (a *arguments) simulate(w *whatIf, s *stateMachines, p printer, index uint64, logOutput io.Writer) error {
+	nodes := map[uint64]*testengine.ContinuedNode{}
+	for nodeID, node := range s.nodes {
+		nodes[nodeID] = &testengine.ContinuedNode{
+			StateMachine:   node.machine,
+			PendingActions: node.pendingActions,
+		}
+	}
+
+	simulated := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(simulated)
+	r, err := w.continuation.Recording(nodes, gzWriter, logOutput)
+	if err != nil {
+		return errors.WithMessage(err, "could not continue recording as a simulation")
+	}
+
+	steps := 0
+	for ; steps < a.simulateSteps && !r.ClientsDrained(); steps++ {
+		if err := r.Step(); err != nil {
+			return errors.WithMessagef(err, "simulation failed at step %d", steps)
+		}
+	}
+
+	if err := gzWriter.Close(); err != nil {
+		return errors.WithMessage(err, "could not write simulated events")
+	}
+
+	reader, err := eventlog.NewReader(simulated)
+	if err != nil {
+		return errors.WithMessage(err, "could not read simulated events")
+	}
+
+	for event, err := reader.ReadEvent(); err != io.EOF; event, err = reader.ReadEvent() {
+		if err != nil {
+			return errors.WithMessage(err, "could not read simulated events")
+		}
+
+		index++
+
+		if excludedByNodeID(event, a.nodeIDs) {
+			continue
+		}
+
+		if a.shouldPrint(event) {
+			if err := p.event(index, withoutActions(event)); err != nil {
+				return err
+			}
+		}
+
+		// The simulation records the actions with each ActionsReceived
+		// event, rather than with the events producing them.
+		if event.Actions != nil {
+			actions := &statemachine.ActionList{}
+			for _, action := range event.Actions.List {
+				actions.PushBack(action)
+			}
+			if err := p.actions(index, event, actions); err != nil {
+				return err
+			}
+		}
+
+		if err := p.flush(); err != nil {
+			return errors.WithMessage(err, "could not write output")
+		}
+	}
+
+	outcome := "the clients drained"
+	if steps == a.simulateSteps {
+		outcome = "the step limit was reached"
+	}
+	fmt.Fprintf(logOutput, "Simulation ended after %d steps, as %s\n", steps, outcome)
+
+	return nil
+}
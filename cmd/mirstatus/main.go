@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// mirstatus compares the status of several Mir nodes, reporting which nodes
+// are behind, which disagree on checkpoint values, and which disagree on the
+// active epoch.  Each status may be read from a file containing the JSON
+// encoded status, or fetched from the status endpoint of a node's debug handler.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+type arguments struct {
+	files   []string
+	urls    []string
+	json    bool
+	timeout time.Duration
+}
+
+func (a *arguments) execute(output io.Writer) error {
+	statuses := make([]*status.StateMachine, 0, len(a.files)+len(a.urls))
+
+	for _, file := range a.files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.WithMessagef(err, "could not read status file %s", file)
+		}
+
+		s, err := parseStatus(data)
+		if err != nil {
+			return errors.WithMessagef(err, "could not parse status file %s", file)
+		}
+		statuses = append(statuses, s)
+	}
+
+	client := &http.Client{Timeout: a.timeout}
+	for _, url := range a.urls {
+		data, err := fetch(client, url)
+		if err != nil {
+			return errors.WithMessagef(err, "could not fetch status from %s", url)
+		}
+
+		s, err := parseStatus(data)
+		if err != nil {
+			return errors.WithMessagef(err, "could not parse status from %s", url)
+		}
+		statuses = append(statuses, s)
+	}
+
+	comparison := status.Compare(statuses...)
+
+	if a.json {
+		data, err := json.MarshalIndent(comparison, "", "  ")
+		if err != nil {
+			return errors.WithMessage(err, "could not marshal comparison")
+		}
+		fmt.Fprintf(output, "%s\n", data)
+		return nil
+	}
+
+	fmt.Fprint(output, comparison.Pretty())
+	return nil
+}
+
+func parseStatus(data []byte) (*status.StateMachine, error) {
+	s := &status.StateMachine{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func fetch(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("unexpected response %s", resp.Status)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+func parseArgs(args []string) (*arguments, error) {
+	app := kingpin.New("mirstatus", "Utility for comparing the status of Mir nodes.")
+	files := app.Arg("file", "A file containing a JSON encoded node status.").ExistingFiles()
+	urls := app.Flag("url", "The URL of a node's JSON status, for instance http://host/debug/mirbft/status (repeatable).").Strings()
+	jsonOutput := app.Flag("json", "Output the comparison as JSON rather than text.").Default("false").Bool()
+	timeout := app.Flag("timeout", "The timeout for fetching each status URL.").Default("10s").Duration()
+
+	_, err := app.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(*files)+len(*urls) < 2 {
+		return nil, errors.Errorf("at least two statuses are required to compare")
+	}
+
+	return &arguments{
+		files:   *files,
+		urls:    *urls,
+		json:    *jsonOutput,
+		timeout: *timeout,
+	}, nil
+}
+
+func main() {
+	kingpin.Version("0.0.1")
+	args, err := parseArgs(os.Args[1:])
+	if err != nil {
+		kingpin.Fatalf("failed to parse arguments, %s, try --help", err)
+	}
+	err = args.execute(os.Stdout)
+	if err != nil {
+		kingpin.Fatalf("%s", err)
+	}
+}
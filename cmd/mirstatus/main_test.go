@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+var _ = Describe("Comparing", func() {
+	var (
+		tmpDir   string
+		statuses []*status.StateMachine
+	)
+
+	writeStatuses := func() []string {
+		files := make([]string, len(statuses))
+		for i, s := range statuses {
+			data, err := json.Marshal(s)
+			Expect(err).NotTo(HaveOccurred())
+			files[i] = filepath.Join(tmpDir, fmt.Sprintf("node%d.json", i))
+			Expect(ioutil.WriteFile(files[i], data, 0600)).To(Succeed())
+		}
+		return files
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "mirstatus_test.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		recorder := (&testengine.Spec{
+			NodeCount:     4,
+			ClientCount:   4,
+			ReqsPerClient: 20,
+		}).Recorder()
+
+		recording, err := recorder.Recording(gzip.NewWriter(ioutil.Discard))
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = recording.DrainClients(5000)
+		Expect(err).NotTo(HaveOccurred())
+
+		statuses = nil
+		for _, node := range recording.Nodes {
+			s, err := node.StateMachine.Status()
+			Expect(err).NotTo(HaveOccurred())
+			statuses = append(statuses, s)
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("requires at least two statuses", func() {
+		_, err := parseArgs([]string{"main.go"})
+		Expect(err).To(MatchError("at least two statuses are required to compare"))
+	})
+
+	It("reports no findings for agreeing nodes", func() {
+		args, err := parseArgs(writeStatuses())
+		Expect(err).NotTo(HaveOccurred())
+
+		output := &bytes.Buffer{}
+		Expect(args.execute(output)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("=== Findings ===\nNone\n"))
+	})
+
+	It("reports lagging nodes, divergent checkpoints and epoch disagreements", func() {
+		statuses[1].LowWatermark -= 20
+		statuses[2].EpochTracker.ActiveEpoch.Number++
+		cp := statuses[3].Checkpoints[0]
+		cp.Value = []byte("divergent")
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(statuses[3])
+		}))
+		defer server.Close()
+
+		files := writeStatuses()[:3]
+		args, err := parseArgs(append(files, "--url", server.URL, "--json"))
+		Expect(err).NotTo(HaveOccurred())
+
+		output := &bytes.Buffer{}
+		Expect(args.execute(output)).To(Succeed())
+
+		comparison := &status.Comparison{}
+		Expect(json.Unmarshal(output.Bytes(), comparison)).To(Succeed())
+		Expect(comparison.Nodes).To(HaveLen(4))
+		Expect(comparison.Nodes[1].Behind).To(Equal(uint64(20)))
+		Expect(comparison.EpochsAgree).To(BeFalse())
+		Expect(comparison.Findings).To(ContainElement(ContainSubstring("node 1 is behind by 20 sequences")))
+		Expect(comparison.Findings).To(ContainElement(ContainSubstring(fmt.Sprintf("checkpoint %d values differ", cp.SeqNo))))
+		Expect(comparison.Findings).To(ContainElement(ContainSubstring("nodes disagree on the active epoch")))
+	})
+})
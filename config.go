@@ -6,6 +6,15 @@ SPDX-License-Identifier: Apache-2.0
 
 package mirbft
 
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
 type Config struct {
 	// Logger provides the logging functions.
 	Logger Logger
@@ -34,3 +43,31 @@ type Config struct {
 	// to a minimum of a few MB.
 	BufferSize uint32
 }
+
+// UpdateConfig changes the tunables of a running node, namely BatchSize,
+// HeartbeatTicks, SuspectTicks, NewEpochTimeoutTicks, and BufferSize, taking
+// effect at their next use.  The Logger of config is ignored.  The change is
+// delivered to the state machine as an event, so is captured by the event
+// interceptor and replays exactly.  The change is not persisted, so a restarted
+// node must be supplied the updated Config.
+func (n *Node) UpdateConfig(ctx context.Context, config *Config) error {
+	if n.stopping() {
+		return ErrStopping
+	}
+
+	if config.BatchSize == 0 {
+		return errors.Errorf("batch size must be greater than 0")
+	}
+
+	if config.NewEpochTimeoutTicks <= 1 {
+		return errors.Errorf("new epoch timeout ticks must be greater than 1")
+	}
+
+	return n.inject(ctx, (&statemachine.EventList{}).UpdateParameters(&state.EventUpdateParameters{
+		BatchSize:            config.BatchSize,
+		HeartbeatTicks:       config.HeartbeatTicks,
+		SuspectTicks:         config.SuspectTicks,
+		NewEpochTimeoutTicks: config.NewEpochTimeoutTicks,
+		BufferSize:           config.BufferSize,
+	}))
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"crypto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+)
+
+type eventCollector struct {
+	events []*state.Event
+}
+
+func (ec *eventCollector) Intercept(event *state.Event) error {
+	ec.events = append(ec.events, event)
+	return nil
+}
+
+var _ = Describe("UpdateConfig", func() {
+	var (
+		tmpDir      string
+		wal         *simplewal.WAL
+		reqStore    *reqstore.Store
+		app         *FakeApp
+		interceptor *eventCollector
+		config      *mirbft.Config
+		node        *mirbft.Node
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "config_test.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		walPath := filepath.Join(tmpDir, "wal")
+		Expect(os.MkdirAll(walPath, 0700)).To(Succeed())
+		wal, err = simplewal.Open(walPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		reqStorePath := filepath.Join(tmpDir, "reqstore")
+		Expect(os.MkdirAll(reqStorePath, 0700)).To(Succeed())
+		reqStore, err = reqstore.Open(reqStorePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		app = &FakeApp{
+			CommitC: make(chan *msgs.QEntry, 100),
+		}
+
+		interceptor = &eventCollector{}
+
+		config = &mirbft.Config{
+			BatchSize:            1,
+			SuspectTicks:         4,
+			HeartbeatTicks:       2,
+			NewEpochTimeoutTicks: 8,
+			BufferSize:           5 * 1024 * 1024,
+			Logger:               mirbft.ConsoleWarnLogger,
+		}
+
+		node, err = mirbft.NewNode(
+			0,
+			config,
+			&mirbft.ProcessorConfig{
+				Link:         NewFakeTransport(1).Link(0),
+				Hasher:       crypto.SHA256,
+				RequestStore: reqStore,
+				App:          app,
+				WAL:          wal,
+				Interceptor:  interceptor,
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = node.InitializeAsNewNode(mirbft.StandardInitialNetworkState(1, 1), []byte("fake"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		wal.Close()
+		reqStore.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("changes the batch size of a running node", func() {
+		sp := &mirbft.SerialProcessor{Node: node}
+
+		drain := func() {
+			for {
+				workItems, err := node.Ready()
+				Expect(err).NotTo(HaveOccurred())
+				if workItems.Len() == 0 {
+					return
+				}
+
+				results, err := sp.Process(workItems)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(node.AddResults(results)).To(Succeed())
+			}
+		}
+
+		drain()
+
+		client := node.Client(0)
+		Expect(client.Propose(context.Background(), 0, clientReq(0, 0))).To(Succeed())
+		for i := 0; i < 10 && len(app.Entries) == 0; i++ {
+			Expect(node.Tick()).To(Succeed())
+			drain()
+		}
+		Expect(app.Entries).To(HaveLen(1))
+		Expect(app.Entries[0].Requests).To(HaveLen(1))
+
+		updated := *config
+		updated.BatchSize = 3
+		Expect(node.UpdateConfig(context.Background(), &updated)).To(Succeed())
+		drain()
+
+		// Without ticks, no batch is cut until it is full.
+		for i := uint64(1); i < 4; i++ {
+			Expect(client.Propose(context.Background(), i, clientReq(0, i))).To(Succeed())
+			drain()
+		}
+		Expect(app.Entries).To(HaveLen(2))
+		Expect(app.Entries[1].Requests).To(HaveLen(3))
+
+		var updates []*state.EventUpdateParameters
+		for _, event := range interceptor.events {
+			if update, ok := event.Type.(*state.Event_UpdateParameters); ok {
+				updates = append(updates, update.UpdateParameters)
+			}
+		}
+		Expect(updates).To(HaveLen(1))
+		Expect(updates[0].BatchSize).To(Equal(uint32(3)))
+		Expect(updates[0].NewEpochTimeoutTicks).To(Equal(uint32(8)))
+	})
+
+	It("rejects invalid configurations", func() {
+		updated := *config
+		updated.NewEpochTimeoutTicks = 1
+		Expect(node.UpdateConfig(context.Background(), &updated)).To(MatchError("new epoch timeout ticks must be greater than 1"))
+
+		updated = *config
+		updated.BatchSize = 0
+		Expect(node.UpdateConfig(context.Background(), &updated)).To(MatchError("batch size must be greater than 0"))
+	})
+})
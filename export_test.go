@@ -0,0 +1,15 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+// MarkStopping begins stopping the node without waking it, as Stop does
+// before waking an idle processor.
+func (n *Node) MarkStopping() {
+	n.stopOnce.Do(func() {
+		close(n.stopC)
+	})
+}
@@ -0,0 +1,325 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+)
+
+// Stage identifies one of the kinds of work performed by a node.
+type Stage string
+
+const (
+	StageWAL          Stage = "wal"
+	StageClient       Stage = "client"
+	StageHash         Stage = "hash"
+	StageNet          Stage = "net"
+	StageApp          Stage = "app"
+	StageReqStore     Stage = "reqstore"
+	StageStateMachine Stage = "state_machine"
+)
+
+var stages = []Stage{
+	StageWAL,
+	StageClient,
+	StageHash,
+	StageNet,
+	StageApp,
+	StageReqStore,
+	StageStateMachine,
+}
+
+// FailureAction determines how a node responds to an error performing the
+// work of a stage.
+type FailureAction int
+
+const (
+	// FailFast stops the node with the error.  This is the default.
+	FailFast FailureAction = iota
+
+	// Retry performs the failed work again after a backoff, stopping the node
+	// once the retries are exhausted.
+	Retry
+
+	// Pause suspends the stage and raises an alert.  The stage performs the
+	// failed work again once resumed via Node.ResumeStage.
+	Pause
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// FailurePolicy configures the response to errors for a stage.  Retried and
+// resumed WAL and app work continues from the failed operation, so the writes,
+// truncations, commits, snapshots, and state transfers which succeeded are not
+// performed again.  The other stages perform the entire batch of failed actions
+// again, so their implementation (for instance the RequestStore) must tolerate
+// repeated operations.
+type FailurePolicy struct {
+	Action FailureAction
+
+	// MaxRetries bounds the number of retries after the initial failure
+	// when the action is Retry.  Zero retries indefinitely.
+	MaxRetries int
+
+	// InitialBackoff is the delay before the first retry, doubling for each
+	// subsequent retry up to MaxBackoff.  If zero, 100ms and 5s are used.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// Alert, if set, is invoked when the stage pauses.  It is invoked from the
+	// stage's go routine, so must not block, though it may resume the stage.
+	Alert func(*StageAlert)
+}
+
+// StageAlert describes a stage paused by its failure policy.
+type StageAlert struct {
+	Stage    Stage
+	Err      error
+	Attempts int
+}
+
+func validateFailurePolicies(policies map[Stage]*FailurePolicy) error {
+	for stage, policy := range policies {
+		known := false
+		for _, s := range stages {
+			if s == stage {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return errors.Errorf("failure policy configured for unknown stage %q", stage)
+		}
+
+		// The state machine only errs on a bug or corrupted input, neither
+		// of which retrying can resolve.
+		if stage == StageStateMachine && policy.Action != FailFast {
+			return errors.Errorf("state machine stage only supports the fail-fast failure policy")
+		}
+
+		if policy.MaxRetries < 0 {
+			return errors.Errorf("failure policy for stage %q has negative MaxRetries", stage)
+		}
+	}
+
+	return nil
+}
+
+// pausedStages tracks the stages paused by their failure policy.
+type pausedStages struct {
+	mutex   sync.Mutex
+	resumeC map[Stage]chan struct{}
+}
+
+func newPausedStages() *pausedStages {
+	return &pausedStages{
+		resumeC: map[Stage]chan struct{}{},
+	}
+}
+
+func (ps *pausedStages) pause(stage Stage) <-chan struct{} {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	resumeC := make(chan struct{})
+	ps.resumeC[stage] = resumeC
+	return resumeC
+}
+
+func (ps *pausedStages) resume(stage Stage) bool {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	resumeC, ok := ps.resumeC[stage]
+	if !ok {
+		return false
+	}
+	close(resumeC)
+	delete(ps.resumeC, stage)
+	return true
+}
+
+// ResumeStage resumes a stage paused by its failure policy, performing the
+// failed work again.  It returns an error if the stage is not paused.
+func (n *Node) ResumeStage(stage Stage) error {
+	if !n.pausedStages.resume(stage) {
+		return errors.Errorf("stage %q is not paused", stage)
+	}
+	return nil
+}
+
+// perform invokes work, responding to any error according to the stage's
+// failure policy.  It returns an error only once the node should stop.
+func (n *Node) perform(stage Stage, exitC <-chan struct{}, work func() error) error {
+	policy, ok := n.processorConfig.FailurePolicies[stage]
+	if !ok {
+		return work()
+	}
+
+	backoff := policy.InitialBackoff
+	if backoff == 0 {
+		backoff = defaultInitialBackoff
+	}
+	maxBackoff := policy.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := work()
+		if err == nil {
+			return nil
+		}
+
+		switch policy.Action {
+		case Retry:
+			if policy.MaxRetries != 0 && attempt > policy.MaxRetries {
+				return errors.WithMessagef(err, "stage %s failed after %d attempts", stage, attempt)
+			}
+
+			n.Config.Logger.Log(LevelWarn, "retrying failed work", "stage", stage, "attempt", attempt, "backoff", backoff, "error", err)
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-timer.C:
+			case <-exitC:
+				timer.Stop()
+				return ErrStopped
+			}
+
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		case Pause:
+			n.Config.Logger.Log(LevelError, "pausing failed stage", "stage", stage, "attempt", attempt, "error", err)
+
+			resumeC := n.pausedStages.pause(stage)
+			if policy.Alert != nil {
+				policy.Alert(&StageAlert{
+					Stage:    stage,
+					Err:      err,
+					Attempts: attempt,
+				})
+			}
+
+			select {
+			case <-resumeC:
+			case <-exitC:
+				return ErrStopped
+			}
+		default:
+			return err
+		}
+	}
+}
+
+// progress records the results of the operations of a batch which succeeded,
+// so that a retried batch resumes from the failed operation.
+type progress struct {
+	results []interface{}
+	next    int
+}
+
+// resume begins another attempt of the batch.
+func (p *progress) resume() {
+	p.next = 0
+}
+
+// perform invokes op, unless an earlier attempt of the batch already performed
+// it, in which case it returns the earlier result.
+func (p *progress) perform(op func() (interface{}, error)) (interface{}, error) {
+	p.next++
+	if p.next <= len(p.results) {
+		return p.results[p.next-1], nil
+	}
+
+	result, err := op()
+	if err != nil {
+		return nil, err
+	}
+
+	p.results = append(p.results, result)
+	return result, nil
+}
+
+// resumableWAL skips the writes and truncations of a batch which succeeded
+// in an earlier attempt, as the WAL rejects rewriting an index.  Syncs are
+// always performed.
+type resumableWAL struct {
+	processor.WAL
+	progress
+}
+
+func (rw *resumableWAL) Write(index uint64, entry *msgs.Persistent) error {
+	_, err := rw.perform(func() (interface{}, error) {
+		return nil, rw.WAL.Write(index, entry)
+	})
+	return err
+}
+
+func (rw *resumableWAL) Truncate(index uint64) error {
+	_, err := rw.perform(func() (interface{}, error) {
+		return nil, rw.WAL.Truncate(index)
+	})
+	return err
+}
+
+// resumableApp skips the commits of a batch which succeeded in an earlier
+// attempt, and returns the earlier results of its snapshots and state
+// transfers, as these depend on the commits preceding them.
+type resumableApp struct {
+	processor.App
+	progress
+}
+
+type snapResult struct {
+	value          []byte
+	pendingReconfs []*msgs.Reconfiguration
+}
+
+type transferResult struct {
+	networkState *msgs.NetworkState
+	err          error
+}
+
+func (ra *resumableApp) Apply(entry *msgs.QEntry) error {
+	_, err := ra.perform(func() (interface{}, error) {
+		return nil, ra.App.Apply(entry)
+	})
+	return err
+}
+
+func (ra *resumableApp) Snap(networkConfig *msgs.NetworkState_Config, clientsState []*msgs.NetworkState_Client) ([]byte, []*msgs.Reconfiguration, error) {
+	result, err := ra.perform(func() (interface{}, error) {
+		value, pendingReconfs, err := ra.App.Snap(networkConfig, clientsState)
+		return &snapResult{value: value, pendingReconfs: pendingReconfs}, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	snap := result.(*snapResult)
+	return snap.value, snap.pendingReconfs, nil
+}
+
+func (ra *resumableApp) TransferTo(seqNo uint64, snap []byte) (*msgs.NetworkState, error) {
+	// A failed transfer is reported to the state machine rather than failing
+	// the batch, so it completes the operation too.
+	result, _ := ra.perform(func() (interface{}, error) {
+		networkState, err := ra.App.TransferTo(seqNo, snap)
+		return &transferResult{networkState: networkState, err: err}, nil
+	})
+	transfer := result.(*transferResult)
+	return transfer.networkState, transfer.err
+}
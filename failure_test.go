@@ -0,0 +1,338 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+)
+
+// FlakyApp fails to apply entries while failures remain, from the failAt'th
+// entry applied.
+type FlakyApp struct {
+	*FakeApp
+
+	mutex    sync.Mutex
+	failures int
+	failAt   int
+	applied  int
+}
+
+func (fa *FlakyApp) Apply(entry *msgs.QEntry) error {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+	fa.applied++
+	if fa.failures > 0 && fa.applied >= fa.failAt {
+		fa.failures--
+		return fmt.Errorf("transient failure")
+	}
+	return fa.FakeApp.Apply(entry)
+}
+
+func (fa *FlakyApp) SetFailures(failures int) {
+	fa.mutex.Lock()
+	defer fa.mutex.Unlock()
+	fa.failures = failures
+}
+
+// FlakyWAL fails to sync while failures remain.
+type FlakyWAL struct {
+	processor.WAL
+
+	failures int
+}
+
+func (fw *FlakyWAL) Sync() error {
+	if fw.failures > 0 {
+		fw.failures--
+		return fmt.Errorf("transient failure")
+	}
+	return fw.WAL.Sync()
+}
+
+var _ = Describe("FailurePolicies", func() {
+	var (
+		tmpDir   string
+		wal      *simplewal.WAL
+		reqStore *reqstore.Store
+		app      *FlakyApp
+		config   *mirbft.ProcessorConfig
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "failure_test.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		walPath := filepath.Join(tmpDir, "wal")
+		Expect(os.MkdirAll(walPath, 0700)).To(Succeed())
+		wal, err = simplewal.Open(walPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		reqStorePath := filepath.Join(tmpDir, "reqstore")
+		Expect(os.MkdirAll(reqStorePath, 0700)).To(Succeed())
+		reqStore, err = reqstore.Open(reqStorePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		app = &FlakyApp{
+			FakeApp: &FakeApp{
+				CommitC: make(chan *msgs.QEntry, 100),
+			},
+		}
+
+		config = &mirbft.ProcessorConfig{
+			Link:         NewFakeTransport(1).Link(0),
+			Hasher:       crypto.SHA256,
+			RequestStore: reqStore,
+			App:          app,
+			WAL:          wal,
+		}
+	})
+
+	AfterEach(func() {
+		wal.Close()
+		reqStore.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	newNode := func() *mirbft.Node {
+		node, err := mirbft.NewNode(
+			0,
+			&mirbft.Config{
+				BatchSize:            1,
+				SuspectTicks:         4,
+				HeartbeatTicks:       2,
+				NewEpochTimeoutTicks: 8,
+				BufferSize:           5 * 1024 * 1024,
+				Logger:               mirbft.ConsoleErrorLogger,
+			},
+			config,
+		)
+		Expect(err).NotTo(HaveOccurred())
+		return node
+	}
+
+	// commit proposes and commits a single request on a manually driven node,
+	// returning the first error encountered processing the work.
+	commit := func(node *mirbft.Node) error {
+		Expect(node.InitializeAsNewNode(mirbft.StandardInitialNetworkState(1, 1), []byte("fake"))).To(Succeed())
+		sp := &mirbft.SerialProcessor{Node: node}
+
+		drain := func() error {
+			for {
+				workItems, err := node.Ready()
+				if err != nil {
+					return err
+				}
+				if workItems.Len() == 0 {
+					return nil
+				}
+
+				results, err := sp.Process(workItems)
+				if err != nil {
+					return err
+				}
+				Expect(node.AddResults(results)).To(Succeed())
+			}
+		}
+
+		if err := drain(); err != nil {
+			return err
+		}
+
+		Expect(node.Client(0).Propose(context.Background(), 0, clientReq(0, 0))).To(Succeed())
+		for i := 0; i < 10 && len(app.Entries) == 0; i++ {
+			if err := drain(); err != nil {
+				return err
+			}
+			Expect(node.Tick()).To(Succeed())
+		}
+
+		return nil
+	}
+
+	It("fails fast by default", func() {
+		app.SetFailures(1)
+		err := commit(newNode())
+		Expect(err).To(MatchError(ContainSubstring("transient failure")))
+		Expect(app.Entries).To(BeEmpty())
+	})
+
+	It("retries failed work with backoff", func() {
+		app.SetFailures(2)
+		config.FailurePolicies = map[mirbft.Stage]*mirbft.FailurePolicy{
+			mirbft.StageApp: {
+				Action:         mirbft.Retry,
+				MaxRetries:     3,
+				InitialBackoff: time.Millisecond,
+			},
+		}
+
+		Expect(commit(newNode())).To(Succeed())
+		Expect(app.Entries).To(HaveLen(1))
+	})
+
+	Describe("retried work", func() {
+		BeforeEach(func() {
+			config.FailurePolicies = map[mirbft.Stage]*mirbft.FailurePolicy{
+				mirbft.StageWAL: {
+					Action:         mirbft.Retry,
+					MaxRetries:     1,
+					InitialBackoff: time.Millisecond,
+				},
+				mirbft.StageApp: {
+					Action:         mirbft.Retry,
+					MaxRetries:     1,
+					InitialBackoff: time.Millisecond,
+				},
+			}
+		})
+
+		It("resumes WAL work from a failed sync", func() {
+			config.WAL = &FlakyWAL{WAL: wal, failures: 1}
+			sp := &mirbft.SerialProcessor{Node: newNode()}
+
+			workItems := processor.NewWorkItems()
+			for i := uint64(1); i <= 2; i++ {
+				workItems.WALActions().Persist(i, &msgs.Persistent{
+					Type: &msgs.Persistent_Suspect{
+						Suspect: &msgs.Suspect{Epoch: i},
+					},
+				})
+			}
+
+			_, err := sp.Process(workItems)
+			Expect(err).NotTo(HaveOccurred())
+
+			var indices []uint64
+			Expect(wal.LoadAll(func(index uint64, _ *msgs.Persistent) {
+				indices = append(indices, index)
+			})).To(Succeed())
+			Expect(indices).To(Equal([]uint64{1, 2}))
+		})
+
+		It("resumes app work from a failed commit", func() {
+			app.failAt = 2
+			app.SetFailures(1)
+			sp := &mirbft.SerialProcessor{Node: newNode()}
+
+			workItems := processor.NewWorkItems()
+			for i := uint64(1); i <= 3; i++ {
+				workItems.AppActions().Commit(&msgs.QEntry{
+					SeqNo: i,
+					Requests: []*msgs.RequestAck{
+						{ClientId: 0, ReqNo: i - 1},
+					},
+				}, 1, 5)
+			}
+
+			_, err := sp.Process(workItems)
+			Expect(err).NotTo(HaveOccurred())
+
+			var seqNos []uint64
+			for _, entry := range app.Entries {
+				seqNos = append(seqNos, entry.SeqNo)
+			}
+			Expect(seqNos).To(Equal([]uint64{1, 2, 3}))
+		})
+	})
+
+	It("fails once the retries are exhausted", func() {
+		app.SetFailures(5)
+		config.FailurePolicies = map[mirbft.Stage]*mirbft.FailurePolicy{
+			mirbft.StageApp: {
+				Action:         mirbft.Retry,
+				MaxRetries:     3,
+				InitialBackoff: time.Millisecond,
+			},
+		}
+
+		err := commit(newNode())
+		Expect(err).To(MatchError(ContainSubstring("stage app failed after 4 attempts")))
+		Expect(app.Entries).To(BeEmpty())
+	})
+
+	It("pauses the stage and alerts until resumed", func() {
+		app.SetFailures(1)
+		alertC := make(chan *mirbft.StageAlert, 1)
+		config.FailurePolicies = map[mirbft.Stage]*mirbft.FailurePolicy{
+			mirbft.StageApp: {
+				Action: mirbft.Pause,
+				Alert: func(alert *mirbft.StageAlert) {
+					alertC <- alert
+				},
+			},
+		}
+		node := newNode()
+
+		exitC := make(chan struct{})
+		defer close(exitC)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+		go node.ProcessAsNewNode(exitC, ticker.C, mirbft.StandardInitialNetworkState(1, 1), []byte("fake"))
+
+		client := node.Client(0)
+		Eventually(func() error {
+			_, err := client.NextReqNo()
+			return err
+		}).Should(Succeed())
+
+		receiptC := make(chan *processor.CommitReceipt, 1)
+		go func() {
+			defer GinkgoRecover()
+			receipt, err := client.ProposeAndWait(context.Background(), 0, clientReq(0, 0))
+			Expect(err).NotTo(HaveOccurred())
+			receiptC <- receipt
+		}()
+
+		var alert *mirbft.StageAlert
+		Eventually(alertC).Should(Receive(&alert))
+		Expect(alert.Stage).To(Equal(mirbft.StageApp))
+		Expect(alert.Err).To(MatchError(ContainSubstring("transient failure")))
+		Expect(alert.Attempts).To(Equal(1))
+		Consistently(receiptC, 50*time.Millisecond).ShouldNot(Receive())
+
+		Expect(node.ResumeStage(mirbft.StageApp)).To(Succeed())
+		Eventually(receiptC).Should(Receive())
+		Expect(app.Entries).To(HaveLen(1))
+
+		Expect(node.ResumeStage(mirbft.StageApp)).To(MatchError(`stage "app" is not paused`))
+	})
+
+	It("rejects invalid policies", func() {
+		config.FailurePolicies = map[mirbft.Stage]*mirbft.FailurePolicy{
+			mirbft.StageStateMachine: {
+				Action: mirbft.Retry,
+			},
+		}
+		_, err := mirbft.NewNode(0, &mirbft.Config{Logger: mirbft.ConsoleErrorLogger}, config)
+		Expect(err).To(MatchError("state machine stage only supports the fail-fast failure policy"))
+
+		config.FailurePolicies = map[mirbft.Stage]*mirbft.FailurePolicy{
+			"unknown": {
+				Action: mirbft.Retry,
+			},
+		}
+		_, err = mirbft.NewNode(0, &mirbft.Config{Logger: mirbft.ConsoleErrorLogger}, config)
+		Expect(err).To(MatchError(`failure policy configured for unknown stage "unknown"`))
+	})
+})
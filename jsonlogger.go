@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLoggerConfig configures a JSONLogger.
+type JSONLoggerConfig struct {
+	// Level is the minimum level logged for components without an override.
+	Level LogLevel
+
+	// ComponentLevels overrides the minimum level for the named components,
+	// as supplied by the state machine via the "component" key, for instance
+	// "epoch_target", "checkpoints", or "msgbuffers".
+	ComponentLevels map[string]LogLevel
+
+	// RepeatInterval, if non-zero, suppresses any message below LevelError
+	// which repeats the text of a message logged by the same component within
+	// the interval.  The number of suppressed messages is reported as
+	// "suppressed" on the next occurrence logged.
+	RepeatInterval time.Duration
+}
+
+// JSONLogger implements Logger by writing each message as a single line JSON
+// object with the keys "time", "level", "component" (if supplied), and "msg",
+// followed by the message's key/value pairs.  It is safe for concurrent use.
+type JSONLogger struct {
+	config *JSONLoggerConfig
+	now    func() time.Time
+
+	mutex   sync.Mutex
+	output  io.Writer
+	repeats map[repeatKey]*repeatState
+}
+
+// maxTrackedRepeats is the number of distinct messages tracked for repeat
+// suppression before expired entries are discarded.
+const maxTrackedRepeats = 1024
+
+type repeatKey struct {
+	component string
+	text      string
+}
+
+type repeatState struct {
+	lastLogged time.Time
+	suppressed int
+}
+
+// NewJSONLogger creates a logger writing to output.  If config is nil, all
+// messages at LevelInfo and above are logged.
+func NewJSONLogger(output io.Writer, config *JSONLoggerConfig) *JSONLogger {
+	if config == nil {
+		config = &JSONLoggerConfig{
+			Level: LevelInfo,
+		}
+	}
+
+	return &JSONLogger{
+		config:  config,
+		now:     time.Now,
+		output:  output,
+		repeats: map[repeatKey]*repeatState{},
+	}
+}
+
+func (jl *JSONLogger) Log(level LogLevel, text string, args ...interface{}) {
+	var component string
+	if len(args) >= 2 && args[0] == "component" {
+		component, _ = args[1].(string)
+		args = args[2:]
+	}
+
+	minLevel, ok := jl.config.ComponentLevels[component]
+	if !ok {
+		minLevel = jl.config.Level
+	}
+
+	if level < minLevel {
+		return
+	}
+
+	jl.mutex.Lock()
+	defer jl.mutex.Unlock()
+
+	now := jl.now()
+
+	var suppressed int
+	if jl.config.RepeatInterval > 0 && level < LevelError {
+		key := repeatKey{component: component, text: text}
+		state, ok := jl.repeats[key]
+		switch {
+		case !ok:
+			if len(jl.repeats) >= maxTrackedRepeats {
+				jl.expireRepeats(now)
+			}
+			jl.repeats[key] = &repeatState{lastLogged: now}
+		case now.Sub(state.lastLogged) < jl.config.RepeatInterval:
+			state.suppressed++
+			return
+		default:
+			suppressed = state.suppressed
+			state.suppressed = 0
+			state.lastLogged = now
+		}
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(`{"time":`)
+	writeJSONValue(&buffer, now.UTC().Format(time.RFC3339Nano))
+	buffer.WriteString(`,"level":`)
+	writeJSONValue(&buffer, levelName(level))
+	if component != "" {
+		buffer.WriteString(`,"component":`)
+		writeJSONValue(&buffer, component)
+	}
+	buffer.WriteString(`,"msg":`)
+	writeJSONValue(&buffer, text)
+
+	for i := 0; i < len(args); i += 2 {
+		buffer.WriteString(",")
+		writeJSONValue(&buffer, fmt.Sprint(args[i]))
+		buffer.WriteString(":")
+		if i+1 < len(args) {
+			writeJSONValue(&buffer, args[i+1])
+		} else {
+			writeJSONValue(&buffer, "%MISSING%")
+		}
+	}
+
+	if suppressed > 0 {
+		buffer.WriteString(`,"suppressed":`)
+		writeJSONValue(&buffer, suppressed)
+	}
+	buffer.WriteString("}\n")
+
+	jl.output.Write(buffer.Bytes())
+}
+
+// expireRepeats discards the repeat state of messages which have not been
+// logged within the repeat interval, so that the tracked messages do not grow
+// without bound.  It must be invoked while holding the mutex.
+func (jl *JSONLogger) expireRepeats(now time.Time) {
+	for key, state := range jl.repeats {
+		if now.Sub(state.lastLogged) >= jl.config.RepeatInterval {
+			delete(jl.repeats, key)
+		}
+	}
+}
+
+// writeJSONValue encodes byte slices as hex (consistent with the console
+// loggers), errors and other unencodable values via their string form.
+func writeJSONValue(buffer *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case []byte:
+		value = fmt.Sprintf("%x", v)
+	case error:
+		value = v.Error()
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprintf("%v", value))
+	}
+	buffer.Write(data)
+}
+
+func levelName(level LogLevel) string {
+	switch level {
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	default:
+		return fmt.Sprintf("level(%d)", level)
+	}
+}
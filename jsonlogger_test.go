@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+)
+
+var _ = Describe("JSONLogger", func() {
+	var (
+		output *bytes.Buffer
+		config *mirbft.JSONLoggerConfig
+		logger *mirbft.JSONLogger
+	)
+
+	lines := func() []map[string]interface{} {
+		result := []map[string]interface{}{}
+		scanner := bufio.NewScanner(bytes.NewReader(output.Bytes()))
+		for scanner.Scan() {
+			line := map[string]interface{}{}
+			Expect(json.Unmarshal(scanner.Bytes(), &line)).To(Succeed())
+			result = append(result, line)
+		}
+		return result
+	}
+
+	BeforeEach(func() {
+		output = &bytes.Buffer{}
+		config = &mirbft.JSONLoggerConfig{
+			Level: mirbft.LevelInfo,
+			ComponentLevels: map[string]mirbft.LogLevel{
+				"epoch_target": mirbft.LevelDebug,
+				"msgbuffers":   mirbft.LevelError,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		logger = mirbft.NewJSONLogger(output, config)
+	})
+
+	It("writes each message as a JSON object", func() {
+		logger.Log(mirbft.LevelInfo, "starting new active epoch", "component", "epoch_active", "epoch_no", 3, "digest", []byte{0xbe, 0xef})
+
+		Expect(lines()).To(HaveLen(1))
+		line := lines()[0]
+		Expect(line).To(HaveKey("time"))
+		Expect(line["level"]).To(Equal("info"))
+		Expect(line["component"]).To(Equal("epoch_active"))
+		Expect(line["msg"]).To(Equal("starting new active epoch"))
+		Expect(line["epoch_no"]).To(Equal(float64(3)))
+		Expect(line["digest"]).To(Equal("beef"))
+	})
+
+	It("applies per component levels", func() {
+		logger.Log(mirbft.LevelDebug, "epoch target debug", "component", "epoch_target")
+		logger.Log(mirbft.LevelDebug, "checkpoints debug", "component", "checkpoints")
+		logger.Log(mirbft.LevelWarn, "dropping buffered msg", "component", "msgbuffers")
+		logger.Log(mirbft.LevelInfo, "untagged info")
+
+		msgs := []interface{}{}
+		for _, line := range lines() {
+			msgs = append(msgs, line["msg"])
+		}
+		Expect(msgs).To(Equal([]interface{}{"epoch target debug", "untagged info"}))
+	})
+
+	When("a repeat interval is configured", func() {
+		BeforeEach(func() {
+			config.ComponentLevels = nil
+			config.RepeatInterval = time.Hour
+		})
+
+		It("suppresses repeated messages from the same component", func() {
+			for i := 0; i < 3; i++ {
+				logger.Log(mirbft.LevelWarn, "dropping buffered msg", "component", "msgbuffers", "type", i)
+				logger.Log(mirbft.LevelWarn, "dropping buffered msg", "component", "checkpoints")
+				logger.Log(mirbft.LevelError, "failed", "component", "msgbuffers")
+			}
+
+			components := []interface{}{}
+			for _, line := range lines() {
+				components = append(components, line["component"], line["msg"])
+			}
+			Expect(components).To(Equal([]interface{}{
+				"msgbuffers", "dropping buffered msg",
+				"checkpoints", "dropping buffered msg",
+				"msgbuffers", "failed",
+				"msgbuffers", "failed",
+				"msgbuffers", "failed",
+			}))
+		})
+	})
+})
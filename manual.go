@@ -0,0 +1,334 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+var ErrNotManual = errors.New("node is not being driven manually")
+
+// manualInbox accepts the inputs (steps, client proposals, and ticks) for a node
+// which is driven manually via Ready and AddResults rather than by the processing
+// go routines.  Because these inputs may arrive from other go routines, the node's
+// work items are guarded by the inbox mutex while in manual mode.
+type manualInbox struct {
+	mutex   sync.Mutex
+	enabled bool
+	node    *Node
+	inputC  chan struct{}
+
+	// outstanding counts the work items returned by Ready whose results
+	// have not yet been added, so that a stopping node knows it has drained.
+	outstanding int
+}
+
+func newManualInbox() *manualInbox {
+	return &manualInbox{
+		inputC: make(chan struct{}, 1),
+	}
+}
+
+// add applies the input to the node's work items and returns true if the node
+// is driven manually, otherwise it returns false and the input must be delivered
+// to the processing go routines.
+func (mi *manualInbox) add(input func(*processor.WorkItems)) bool {
+	mi.mutex.Lock()
+	defer mi.mutex.Unlock()
+	if !mi.enabled {
+		return false
+	}
+
+	input(mi.node.workItems)
+	mi.wake()
+
+	return true
+}
+
+// wake signals InputC without blocking.
+func (mi *manualInbox) wake() {
+	select {
+	case mi.inputC <- struct{}{}:
+	default:
+	}
+}
+
+// InitializeAsNewNode prepares the node to be driven manually via Ready and
+// AddResults, as an alternative to ProcessAsNewNode.  The node must not be
+// started via ProcessAsNewNode or RestartProcessing after this call.
+func (n *Node) InitializeAsNewNode(initialNetworkState *msgs.NetworkState, initialCheckpointValue []byte) error {
+	return n.initializeManual(func() error {
+		return n.initializeAsNewNode(initialNetworkState, initialCheckpointValue)
+	})
+}
+
+// InitializeAsRestartedNode prepares the node to be driven manually via Ready and
+// AddResults from its existing WAL, as an alternative to RestartProcessing.
+func (n *Node) InitializeAsRestartedNode() error {
+	return n.initializeManual(n.initializeAsRestartedNode)
+}
+
+func (n *Node) initializeManual(initialize func() error) error {
+	n.manualInbox.mutex.Lock()
+	defer n.manualInbox.mutex.Unlock()
+	if n.manualInbox.enabled {
+		return errors.Errorf("node already initialized")
+	}
+
+	if err := initialize(); err != nil {
+		return err
+	}
+
+	n.manualInbox.node = n
+	n.manualInbox.enabled = true
+	return nil
+}
+
+// Ready applies all pending events to the state machine, then returns the resulting
+// work items, removing them from the node.  The caller is responsible for performing
+// the work, in whatever order or concurrency it desires, then returning the results
+// via AddResults.  The WAL, hash, net, and request store work may be performed using
+// the corresponding functions from the processor package, while the client and app
+// work should be performed via the node's ProcessClientActions and ProcessAppActions.
+func (n *Node) Ready() (*processor.WorkItems, error) {
+	n.manualInbox.mutex.Lock()
+	defer n.manualInbox.mutex.Unlock()
+	if !n.manualInbox.enabled {
+		return nil, ErrNotManual
+	}
+
+	if err := n.workErrNotifier.Err(); err != nil {
+		return nil, err
+	}
+
+	if n.workItems.ResultEvents().Len() > 0 {
+		events := n.workItems.ResultEvents()
+		n.workItems.ClearResultEvents()
+		actions, err := n.processStateMachineEvents(events)
+		if err != nil {
+			n.workErrNotifier.Fail(err)
+			return nil, err
+		}
+		n.workItems.AddStateMachineResults(actions)
+		n.observeStateMachine()
+
+		if err := n.statusWatchers.notify(n.stateMachine); err != nil {
+			n.workErrNotifier.Fail(err)
+			return nil, err
+		}
+	}
+
+	n.observeWorkItems(n.workItems)
+
+	if n.stopping() && n.manualInbox.outstanding == 0 && n.workItems.Len() == 0 {
+		n.workErrNotifier.Fail(ErrStopped)
+		return nil, ErrStopped
+	}
+
+	workItems := n.workItems
+	n.workItems = processor.NewWorkItems()
+	if workItems.Len() > 0 {
+		n.manualInbox.outstanding++
+	}
+	return workItems, nil
+}
+
+// AddResults returns the results of performing the work from Ready to the node.
+// The results should be accumulated using the Add*Results methods of the work items.
+func (n *Node) AddResults(results *processor.WorkItems) error {
+	if !n.manualInbox.add(func(workItems *processor.WorkItems) {
+		workItems.AddWorkItems(results)
+		if n.manualInbox.outstanding > 0 {
+			n.manualInbox.outstanding--
+		}
+	}) {
+		return ErrNotManual
+	}
+	return nil
+}
+
+// Tick advances the state machine's notion of time for a node which is driven
+// manually.
+func (n *Node) Tick() error {
+	if n.stopping() {
+		return ErrStopping
+	}
+
+	if !n.manualInbox.add(func(workItems *processor.WorkItems) {
+		workItems.ResultEvents().TickElapsed()
+	}) {
+		return ErrNotManual
+	}
+	return nil
+}
+
+// InputC returns a channel which receives whenever new inputs are added to a
+// manually driven node, indicating that Ready may return new work.
+func (n *Node) InputC() <-chan struct{} {
+	return n.manualInbox.inputC
+}
+
+func (n *Node) manualStatus() (bool, *status.StateMachine, error) {
+	n.manualInbox.mutex.Lock()
+	defer n.manualInbox.mutex.Unlock()
+	if !n.manualInbox.enabled {
+		return false, nil, nil
+	}
+
+	s, err := n.stateMachine.Status()
+	return true, s, err
+}
+
+// SerialProcessor drives a manually initialized node, performing all of its work
+// serially in the calling go routine.  This yields deterministic execution given
+// a deterministic order of inputs.
+type SerialProcessor struct {
+	Node *Node
+}
+
+// Process performs all of the given work serially, returning the results.
+// Failed work is handled according to the node's failure policies, though a
+// paused stage blocks processing until it is resumed or the node exits.
+func (sp *SerialProcessor) Process(workItems *processor.WorkItems) (*processor.WorkItems, error) {
+	n := sp.Node
+	exitC := n.workErrNotifier.ExitC()
+	results := processor.NewWorkItems()
+
+	if workItems.WALActions().Len() > 0 {
+		var walResults *statemachine.ActionList
+		wal := &resumableWAL{WAL: n.processorConfig.WAL}
+		err := n.perform(StageWAL, exitC, func() (err error) {
+			wal.resume()
+			walResults, err = processor.ProcessWALActions(wal, workItems.WALActions())
+			return errors.WithMessage(err, "could not perform WAL actions")
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.AddWALResults(walResults)
+	}
+
+	if workItems.ClientActions().Len() > 0 {
+		var clientResults *statemachine.EventList
+		err := n.perform(StageClient, exitC, func() (err error) {
+			clientResults, err = n.ProcessClientActions(workItems.ClientActions())
+			return errors.WithMessage(err, "could not perform client actions")
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.AddClientResults(clientResults)
+	}
+
+	if workItems.HashActions().Len() > 0 {
+		var hashResults *statemachine.EventList
+		err := n.perform(StageHash, exitC, func() (err error) {
+			hashResults, err = processor.ProcessHashActions(n.processorConfig.Hasher, workItems.HashActions())
+			return errors.WithMessage(err, "could not perform hash actions")
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.AddHashResults(hashResults)
+	}
+
+	if workItems.NetActions().Len() > 0 {
+		var netResults *statemachine.EventList
+		err := n.perform(StageNet, exitC, func() (err error) {
+			netResults, err = processor.ProcessNetActions(n.ID, n.processorConfig.Link, workItems.NetActions())
+			return errors.WithMessage(err, "could not perform net actions")
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.AddNetResults(netResults)
+	}
+
+	if workItems.AppActions().Len() > 0 {
+		var appResults *statemachine.EventList
+		app := &resumableApp{App: n.processorConfig.App}
+		err := n.perform(StageApp, exitC, func() (err error) {
+			app.resume()
+			appResults, err = n.processAppActions(app, workItems.AppActions())
+			return errors.WithMessage(err, "could not perform app actions")
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.AddAppResults(appResults)
+	}
+
+	if workItems.ReqStoreEvents().Len() > 0 {
+		var reqStoreResults *statemachine.EventList
+		err := n.perform(StageReqStore, exitC, func() (err error) {
+			reqStoreResults, err = processor.ProcessReqStoreEvents(n.processorConfig.RequestStore, workItems.ReqStoreEvents())
+			return errors.WithMessage(err, "could not perform reqstore actions")
+		})
+		if err != nil {
+			return nil, err
+		}
+		results.AddReqStoreResults(reqStoreResults)
+	}
+
+	return results, nil
+}
+
+// Run repeatedly processes the node's work until exitC is closed or an error
+// occurs, ticking the node on each receipt from tickC.
+func (sp *SerialProcessor) Run(exitC <-chan struct{}, tickC <-chan time.Time) error {
+	n := sp.Node
+	for {
+		select {
+		case <-exitC:
+			n.workErrNotifier.Fail(ErrStopped)
+			return ErrStopped
+		default:
+		}
+
+		workItems, err := n.Ready()
+		if err != nil {
+			n.workErrNotifier.Fail(err)
+			return err
+		}
+
+		if workItems.Len() == 0 {
+			select {
+			case <-n.InputC():
+			case <-tickC:
+				// Once stopping, ticks are refused, and the next call
+				// to Ready completes the drain.
+				if err := n.Tick(); err != nil && err != ErrStopping {
+					n.workErrNotifier.Fail(err)
+					return err
+				}
+			case <-exitC:
+				n.workErrNotifier.Fail(ErrStopped)
+				return ErrStopped
+			}
+			continue
+		}
+
+		results, err := sp.Process(workItems)
+		if err != nil {
+			n.workErrNotifier.Fail(err)
+			return err
+		}
+
+		if err := n.AddResults(results); err != nil {
+			n.workErrNotifier.Fail(err)
+			return err
+		}
+	}
+}
@@ -0,0 +1,422 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/metrics"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+	"github.com/hyperledger-labs/mirbft/pkg/tracing"
+)
+
+var _ = Describe("SerialProcessor", func() {
+	var (
+		tmpDir   string
+		wal      *simplewal.WAL
+		reqStore *reqstore.Store
+		app      *FakeApp
+		exporter *metrics.Prometheus
+		traces   *bytes.Buffer
+		node     *mirbft.Node
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "serial_test.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		walPath := filepath.Join(tmpDir, "wal")
+		Expect(os.MkdirAll(walPath, 0700)).To(Succeed())
+		wal, err = simplewal.Open(walPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		reqStorePath := filepath.Join(tmpDir, "reqstore")
+		Expect(os.MkdirAll(reqStorePath, 0700)).To(Succeed())
+		reqStore, err = reqstore.Open(reqStorePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		app = &FakeApp{
+			CommitC: make(chan *msgs.QEntry, 100),
+		}
+
+		exporter = metrics.NewPrometheus()
+		traces = &bytes.Buffer{}
+
+		node, err = mirbft.NewNode(
+			0,
+			&mirbft.Config{
+				BatchSize:            1,
+				SuspectTicks:         4,
+				HeartbeatTicks:       2,
+				NewEpochTimeoutTicks: 8,
+				BufferSize:           5 * 1024 * 1024,
+				Logger:               mirbft.ConsoleWarnLogger,
+			},
+			&mirbft.ProcessorConfig{
+				Link:         NewFakeTransport(1).Link(0),
+				Hasher:       crypto.SHA256,
+				RequestStore: reqStore,
+				App:          app,
+				WAL:          wal,
+				Metrics:      exporter,
+				Tracer:       tracing.NewJSONLines(traces),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		err = node.InitializeAsNewNode(mirbft.StandardInitialNetworkState(1, 1), []byte("fake"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		wal.Close()
+		reqStore.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("commits requests without any additional go routines", func() {
+		sp := &mirbft.SerialProcessor{Node: node}
+
+		drain := func() {
+			for {
+				workItems, err := node.Ready()
+				Expect(err).NotTo(HaveOccurred())
+				if workItems.Len() == 0 {
+					return
+				}
+
+				results, err := sp.Process(workItems)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(node.AddResults(results)).To(Succeed())
+			}
+		}
+
+		drain()
+
+		client := node.Client(0)
+		for i := uint64(0); i < 10; i++ {
+			Expect(client.Propose(context.Background(), i, clientReq(0, i))).To(Succeed())
+			drain()
+		}
+
+		for i := 0; i < 10 && len(app.Entries) < 10; i++ {
+			Expect(node.Tick()).To(Succeed())
+			drain()
+		}
+
+		Expect(app.Entries).To(HaveLen(10))
+		for i, entry := range app.Entries {
+			Expect(entry.Requests).To(HaveLen(1))
+			Expect(entry.Requests[0].ReqNo).To(Equal(uint64(i)))
+		}
+
+		status, err := node.Status(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(status.NodeID).To(Equal(uint64(0)))
+
+		server := httptest.NewServer(exporter)
+		defer server.Close()
+		resp, err := http.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(body)).To(ContainSubstring("mirbft_committed_requests_total 10\n"))
+		Expect(string(body)).To(ContainSubstring(`mirbft_sync_duration_seconds_count{store="wal"}`))
+		Expect(string(body)).To(ContainSubstring("mirbft_epoch 1\n"))
+	})
+
+	It("traces each stage of a request through the pipeline", func() {
+		sp := &mirbft.SerialProcessor{Node: node}
+
+		drain := func() {
+			for {
+				workItems, err := node.Ready()
+				Expect(err).NotTo(HaveOccurred())
+				if workItems.Len() == 0 {
+					return
+				}
+
+				results, err := sp.Process(workItems)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(node.AddResults(results)).To(Succeed())
+			}
+		}
+
+		drain()
+
+		// The checkpoint interval is 5, so 5 requests are checkpointed.
+		client := node.Client(0)
+		for i := uint64(0); i < 5; i++ {
+			Expect(client.Propose(context.Background(), i, clientReq(0, i))).To(Succeed())
+			drain()
+		}
+
+		for i := 0; i < 10 && len(app.Entries) < 5; i++ {
+			Expect(node.Tick()).To(Succeed())
+			drain()
+		}
+		Expect(app.Entries).To(HaveLen(5))
+
+		type span struct {
+			TraceID string `json:"trace_id"`
+			ReqNo   uint64 `json:"req_no"`
+			Stage   string `json:"stage"`
+			SeqNo   uint64 `json:"seq_no"`
+		}
+
+		var stages []string
+		var traceIDs []string
+		var seqNo uint64
+		decoder := json.NewDecoder(traces)
+		for decoder.More() {
+			s := &span{}
+			Expect(decoder.Decode(s)).To(Succeed())
+			if s.ReqNo != 2 {
+				continue
+			}
+			stages = append(stages, s.Stage)
+			traceIDs = append(traceIDs, s.TraceID)
+			if s.SeqNo != 0 {
+				seqNo = s.SeqNo
+			}
+		}
+
+		Expect(stages).To(Equal([]string{
+			"proposed",
+			"persisted",
+			"acked",
+			"correct",
+			"strong",
+			"preprepared",
+			"prepared",
+			"committed",
+			"applied",
+			"checkpointed",
+		}))
+		for _, traceID := range traceIDs {
+			Expect(traceID).To(Equal(traceIDs[0]))
+		}
+		Expect(seqNo).To(Equal(app.Entries[2].SeqNo))
+	})
+
+	It("delivers status snapshots when watched facets change", func() {
+		sp := &mirbft.SerialProcessor{Node: node}
+
+		drain := func() {
+			for {
+				workItems, err := node.Ready()
+				Expect(err).NotTo(HaveOccurred())
+				if workItems.Len() == 0 {
+					return
+				}
+
+				results, err := sp.Process(workItems)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(node.AddResults(results)).To(Succeed())
+			}
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		epochC := node.WatchStatus(ctx, mirbft.WatchEpoch)
+		checkpointC := node.WatchStatus(ctx, mirbft.WatchCheckpoints)
+
+		drain()
+
+		var s *status.StateMachine
+		Expect(epochC).To(Receive(&s))
+		Expect(s.EpochTracker.ActiveEpoch.Number).To(Equal(uint64(1)))
+		Expect(checkpointC).To(Receive())
+
+		client := node.Client(0)
+		for i := uint64(0); i < 10; i++ {
+			Expect(client.Propose(context.Background(), i, clientReq(0, i))).To(Succeed())
+			drain()
+		}
+
+		for i := 0; i < 10 && len(app.Entries) < 10; i++ {
+			Expect(node.Tick()).To(Succeed())
+			drain()
+		}
+
+		// The checkpoints at seqNo 5 and 10 have both become stable, but
+		// have been coalesced into a single snapshot.
+		Expect(checkpointC).To(Receive(&s))
+		Expect(s.Checkpoints).To(ContainElement(WithTransform(func(cp *status.Checkpoint) bool {
+			return cp.SeqNo == 10 && cp.NetQuorum && cp.LocalDecision
+		}, BeTrue())))
+		Expect(checkpointC).NotTo(Receive())
+
+		// The epoch has become active, but the epoch number is unchanged.
+		Expect(epochC).To(Receive(&s))
+		Expect(s.EpochTracker.ActiveEpoch.Number).To(Equal(uint64(1)))
+		Expect(epochC).NotTo(Receive())
+
+		cancel()
+		Eventually(epochC).Should(BeClosed())
+		Eventually(checkpointC).Should(BeClosed())
+	})
+
+	Describe("ProposeAndWait", func() {
+		type result struct {
+			receipt *processor.CommitReceipt
+			err     error
+		}
+
+		var (
+			drain          func()
+			proposeAndWait func(ctx context.Context) <-chan result
+		)
+
+		BeforeEach(func() {
+			sp := &mirbft.SerialProcessor{Node: node}
+			drain = func() {
+				for {
+					workItems, err := node.Ready()
+					Expect(err).NotTo(HaveOccurred())
+					if workItems.Len() == 0 {
+						return
+					}
+
+					results, err := sp.Process(workItems)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(node.AddResults(results)).To(Succeed())
+				}
+			}
+
+			proposeAndWait = func(ctx context.Context) <-chan result {
+				resultC := make(chan result, 1)
+				go func() {
+					receipt, err := node.Client(0).ProposeAndWait(ctx, 0, clientReq(0, 0))
+					resultC <- result{receipt: receipt, err: err}
+				}()
+				return resultC
+			}
+
+			drain()
+		})
+
+		// commit drives the node until each result is received.
+		commit := func(resultCs ...<-chan result) []result {
+			var results []result
+			for _, resultC := range resultCs {
+				Eventually(func() bool {
+					Expect(node.Tick()).To(Succeed())
+					drain()
+					select {
+					case r := <-resultC:
+						results = append(results, r)
+						return true
+					default:
+						return false
+					}
+				}).Should(BeTrue())
+			}
+			return results
+		}
+
+		It("returns the commit receipt", func() {
+			results := commit(proposeAndWait(context.Background()))
+			Expect(results[0].err).NotTo(HaveOccurred())
+			Expect(results[0].receipt).To(Equal(&processor.CommitReceipt{
+				SeqNo:           1,
+				BatchPosition:   0,
+				Epoch:           1,
+				CheckpointSeqNo: 5,
+			}))
+		})
+
+		It("returns the commit receipt to duplicate proposals", func() {
+			results := commit(proposeAndWait(context.Background()), proposeAndWait(context.Background()))
+			Expect(results[0].err).NotTo(HaveOccurred())
+			Expect(results[1].err).NotTo(HaveOccurred())
+			Expect(results[1].receipt).To(Equal(results[0].receipt))
+		})
+
+		It("returns once the context is cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			resultC := proposeAndWait(ctx)
+			Consistently(resultC).ShouldNot(Receive())
+
+			cancel()
+			var r result
+			Eventually(resultC).Should(Receive(&r))
+			Expect(r.err).To(Equal(context.Canceled))
+			Expect(app.Entries).To(BeEmpty())
+		})
+	})
+
+	It("runs until stopped", func() {
+		exitC := make(chan struct{})
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+
+		errC := make(chan error, 1)
+		go func() {
+			errC <- (&mirbft.SerialProcessor{Node: node}).Run(exitC, ticker.C)
+		}()
+
+		client := node.Client(0)
+		Eventually(func() error {
+			_, err := client.NextReqNo()
+			return err
+		}).Should(Succeed())
+
+		receipt, err := client.ProposeAndWait(context.Background(), 0, clientReq(0, 0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(receipt.SeqNo).To(Equal(uint64(1)))
+
+		close(exitC)
+		Eventually(errC).Should(Receive(Equal(mirbft.ErrStopped)))
+	})
+
+	It("drains when ticked as it stops", func() {
+		tickC := make(chan time.Time)
+		errC := make(chan error, 1)
+		go func() {
+			errC <- (&mirbft.SerialProcessor{Node: node}).Run(make(chan struct{}), tickC)
+		}()
+
+		Eventually(func() error {
+			_, err := node.Client(0).NextReqNo()
+			return err
+		}).Should(Succeed())
+		time.Sleep(10 * time.Millisecond)
+
+		// The idle processor receives a tick before the wake from Stop.
+		node.MarkStopping()
+		select {
+		case tickC <- time.Time{}:
+		case err := <-errC:
+			errC <- err
+		}
+		Eventually(errC).Should(Receive(Equal(mirbft.ErrStopped)))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err := node.Stop(ctx)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
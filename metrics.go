@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// meteredWAL reports the latency of each WAL sync.
+type meteredWAL struct {
+	processor.WAL
+	metrics processor.Metrics
+}
+
+func (mw *meteredWAL) Sync() error {
+	defer observeDuration(mw.metrics, processor.MetricSyncDuration, time.Now(), "store", "wal")
+	return mw.WAL.Sync()
+}
+
+// meteredRequestStore reports the latency of each request store sync.
+type meteredRequestStore struct {
+	processor.RequestStore
+	metrics processor.Metrics
+}
+
+func (mrs *meteredRequestStore) Sync() error {
+	defer observeDuration(mrs.metrics, processor.MetricSyncDuration, time.Now(), "store", "reqstore")
+	return mrs.RequestStore.Sync()
+}
+
+func observeDuration(metrics processor.Metrics, name string, start time.Time, labels ...string) {
+	metrics.ObserveHistogram(name, time.Since(start).Seconds(), labels...)
+}
+
+// observeStage records the time spent performing a batch of work for the named stage.
+func (n *Node) observeStage(stage Stage, start time.Time) {
+	observeDuration(n.metrics, processor.MetricStageDuration, start, "stage", string(stage))
+}
+
+// observeWorkItems records the length of each of the outstanding work queues.
+func (n *Node) observeWorkItems(workItems *processor.WorkItems) {
+	for _, queue := range []struct {
+		name   string
+		length int
+	}{
+		{"wal", workItems.WALActions().Len()},
+		{"client", workItems.ClientActions().Len()},
+		{"hash", workItems.HashActions().Len()},
+		{"net", workItems.NetActions().Len()},
+		{"app", workItems.AppActions().Len()},
+		{"reqstore", workItems.ReqStoreEvents().Len()},
+		{"state_machine", workItems.ResultEvents().Len()},
+	} {
+		n.metrics.SetGauge(processor.MetricWorkItems, float64(queue.length), "queue", queue.name)
+	}
+}
+
+// observeStateMachine records the current epoch and buffer sizes of the state
+// machine.  It must be invoked from the go routine applying events to the state machine.
+func (n *Node) observeStateMachine() {
+	epoch := n.stateMachine.CurrentEpoch()
+	if epoch > n.lastEpoch {
+		if n.lastEpoch != 0 {
+			n.metrics.AddCounter(processor.MetricEpochChanges, float64(epoch-n.lastEpoch))
+		}
+		n.lastEpoch = epoch
+		n.metrics.SetGauge(processor.MetricEpoch, float64(epoch))
+	}
+
+	for id, size := range n.stateMachine.BufferSizes() {
+		n.metrics.SetGauge(processor.MetricBufferedBytes, float64(size), "node", strconv.FormatUint(id, 10))
+	}
+}
+
+// observeCommits records the batches and requests committed in the given app actions.
+func (n *Node) observeCommits(actions *statemachine.ActionList) {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		commit, ok := action.Type.(*state.Action_Commit)
+		if !ok {
+			continue
+		}
+
+		n.metrics.AddCounter(processor.MetricCommittedBatches, 1)
+		n.metrics.AddCounter(processor.MetricCommittedRequests, float64(len(commit.Commit.Batch.Requests)))
+	}
+}
@@ -24,11 +24,15 @@ import (
 	"github.com/hyperledger-labs/mirbft/pkg/processor"
 	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 	"github.com/hyperledger-labs/mirbft/pkg/status"
+	"github.com/hyperledger-labs/mirbft/pkg/tracing"
 	"github.com/pkg/errors"
 )
 
 var ErrStopped = fmt.Errorf("stopped at caller request")
 
+// ErrStopping is returned for inputs supplied to a node after Stop is invoked.
+var ErrStopping = fmt.Errorf("node is stopping")
+
 type replicas struct {
 	mutex    sync.Mutex
 	eventC   chan *statemachine.EventList
@@ -44,18 +48,141 @@ func (r *replicas) replica(id uint64) *processor.Replica {
 type Client struct {
 	client          *processor.Client
 	resultC         chan<- *statemachine.EventList
+	manualInbox     *manualInbox
 	workErrNotifier *workErrNotifier
+	stopC           <-chan struct{}
+	tracer          *tracing.Observer
 }
 
 func (c *Client) NextReqNo() (uint64, error) {
 	return c.client.NextReqNo()
 }
 
+// Propose proposes a request for the given request number.  If the request number
+// is beyond the client's window, or the client holds too many bytes of requests,
+// Propose blocks until the window advances, or the context ends.  If the client
+// has exceeded its configured rate limit, a *processor.RateLimitError is returned,
+// though duplicate proposals are not charged against the limit.
 func (c *Client) Propose(ctx context.Context, reqNo uint64, data []byte) error {
+	var result *statemachine.EventList
+	err := c.retryOnBackpressure(ctx, func() (err error) {
+		result, err = c.client.Propose(reqNo, data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
 
-	result, err := c.client.Propose(reqNo, data)
+	if c.tracer != nil {
+		c.tracer.Proposed(result)
+	}
+
+	return c.submit(ctx, result)
+}
+
+// ProposeAndWait proposes a request, then blocks until the request number commits
+// and the committing batch has been applied locally, returning the receipt describing
+// where the request committed.  If the request number was filled with a different
+// request, including the null request, a *processor.CommitConflictError is returned.
+func (c *Client) ProposeAndWait(ctx context.Context, reqNo uint64, data []byte) (*processor.CommitReceipt, error) {
+	var result *statemachine.EventList
+	var watch *processor.CommitWatch
+	err := c.retryOnBackpressure(ctx, func() (err error) {
+		result, watch, err = c.client.ProposeAndWatch(reqNo, data)
+		return err
+	})
 	if err != nil {
+		return nil, err
+	}
+	defer watch.Cancel()
+
+	if c.tracer != nil {
+		c.tracer.Proposed(result)
+	}
+
+	if err := c.submit(ctx, result); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-watch.Done():
+		return watch.Result()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.workErrNotifier.ExitC():
+		return nil, c.workErrNotifier.Err()
+	}
+}
+
+// ProposeNull proposes the null request for the given request number.  This
+// is intended for clients which have crashed without persisting their requests
+// and therefore cannot replay the originals for uncommitted request numbers.
+// Like Propose, it may fail with a *processor.RateLimitError.
+func (c *Client) ProposeNull(ctx context.Context, reqNo uint64) error {
+	var result *statemachine.EventList
+	err := c.retryOnBackpressure(ctx, func() (err error) {
+		result, err = c.client.ProposeNull(reqNo)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.submit(ctx, result)
+}
+
+// ProposeNullThrough proposes the null request for every uncommitted request
+// number from the client's low watermark through reqNo, inclusive.  After these
+// requests commit, the client may resume proposing from reqNo+1.  Each null
+// request is charged against the client's rate limit, and if the limit does not
+// admit them all, none are proposed and a *processor.RateLimitError is returned.
+func (c *Client) ProposeNullThrough(ctx context.Context, reqNo uint64) error {
+	var result *statemachine.EventList
+	err := c.retryOnBackpressure(ctx, func() (err error) {
+		result, err = c.client.ProposeNullThrough(reqNo)
 		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.submit(ctx, result)
+}
+
+// retryOnBackpressure invokes propose until it does not fail with
+// processor.ErrBackpressure, waiting for the client window to advance
+// between attempts.
+func (c *Client) retryOnBackpressure(ctx context.Context, propose func() error) error {
+	select {
+	case <-c.stopC:
+		return ErrStopping
+	default:
+	}
+
+	for {
+		capacityC := c.client.CapacityC()
+		err := propose()
+		if err != processor.ErrBackpressure {
+			return err
+		}
+
+		select {
+		case <-capacityC:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.stopC:
+			return ErrStopping
+		case <-c.workErrNotifier.ExitC():
+			return c.workErrNotifier.Err()
+		}
+	}
+}
+
+func (c *Client) submit(ctx context.Context, result *statemachine.EventList) error {
+	if c.manualInbox.add(func(workItems *processor.WorkItems) {
+		workItems.AddClientResults(result)
+	}) {
+		return nil
 	}
 
 	select {
@@ -79,9 +206,17 @@ type Node struct {
 
 	replicas *replicas
 
+	metrics         processor.Metrics
+	tracer          *tracing.Observer
+	lastEpoch       uint64
 	stateMachine    *statemachine.StateMachine
 	workItems       *processor.WorkItems
 	workErrNotifier *workErrNotifier
+	manualInbox     *manualInbox
+	statusWatchers  *statusWatchers
+	stopOnce        sync.Once
+	stopC           chan struct{}
+	pausedStages    *pausedStages
 
 	statusC          chan chan *status.StateMachine
 	walActionsC      chan *statemachine.ActionList
@@ -98,6 +233,7 @@ type Node struct {
 	reqStoreResultsC chan *statemachine.EventList
 	resultEventsC    chan *statemachine.EventList
 	resultResultsC   chan *statemachine.ActionList
+	workDoneC        chan struct{}
 	clients          *processor.Clients
 }
 
@@ -139,6 +275,32 @@ func NewNode(
 	config *Config,
 	processorConfig *ProcessorConfig,
 ) (*Node, error) {
+	metrics := processorConfig.Metrics
+	if metrics == nil {
+		metrics = processor.NopMetrics{}
+	} else {
+		meteredConfig := *processorConfig
+		meteredConfig.WAL = &meteredWAL{WAL: processorConfig.WAL, metrics: metrics}
+		meteredConfig.RequestStore = &meteredRequestStore{RequestStore: processorConfig.RequestStore, metrics: metrics}
+		processorConfig = &meteredConfig
+	}
+
+	if err := validateFailurePolicies(processorConfig.FailurePolicies); err != nil {
+		return nil, err
+	}
+
+	rateLimiter := processor.NewRateLimiter(processorConfig.RateLimits, metrics)
+
+	stateMachine := &statemachine.StateMachine{
+		Logger: logAdapter{Logger: config.Logger},
+	}
+
+	var tracer *tracing.Observer
+	if processorConfig.Tracer != nil {
+		tracer = tracing.NewObserver(id, processorConfig.Tracer)
+		stateMachine.RequestTracer = tracer
+	}
+
 	return &Node{
 		ID:              id,
 		Config:          config,
@@ -146,18 +308,28 @@ func NewNode(
 
 		replicas: &replicas{
 			eventC: make(chan *statemachine.EventList),
+			replicas: processor.Replicas{
+				RateLimiter: rateLimiter,
+			},
 		},
-		stateMachine: &statemachine.StateMachine{
-			Logger: logAdapter{Logger: config.Logger},
-		},
+		metrics:      metrics,
+		tracer:       tracer,
+		stateMachine: stateMachine,
 		clients: &processor.Clients{
-			RequestStore: processorConfig.RequestStore,
-			Hasher:       processorConfig.Hasher,
+			RequestStore:   processorConfig.RequestStore,
+			Hasher:         processorConfig.Hasher,
+			MaxClientBytes: processorConfig.MaxClientBytes,
+			RateLimiter:    rateLimiter,
 		},
 		workItems:       processor.NewWorkItems(),
 		workErrNotifier: newWorkErrNotifier(),
+		manualInbox:     newManualInbox(),
+		statusWatchers:  newStatusWatchers(),
+		stopC:           make(chan struct{}),
+		pausedStages:    newPausedStages(),
 
 		statusC:          make(chan chan *status.StateMachine),
+		workDoneC:        make(chan struct{}),
 		walActionsC:      make(chan *statemachine.ActionList),
 		walResultsC:      make(chan *statemachine.ActionList),
 		clientActionsC:   make(chan *statemachine.ActionList),
@@ -183,6 +355,10 @@ func NewNode(
 // This final status may be relied upon if it is non-nil.  If the serializer exited at the user's
 // request (because the done channel was closed), then ErrStopped is returned.
 func (n *Node) Status(ctx context.Context) (*status.StateMachine, error) {
+	if ok, s, err := n.manualStatus(); ok {
+		return s, err
+	}
+
 	statusC := make(chan *status.StateMachine, 1)
 	select {
 	case <-ctx.Done():
@@ -203,6 +379,10 @@ func (n *Node) Status(ctx context.Context) (*status.StateMachine, error) {
 }
 
 func (n *Node) Step(ctx context.Context, source uint64, msg *msgs.Msg) error {
+	if n.stopping() {
+		return ErrStopping
+	}
+
 	r := n.replicas.replica(source)
 
 	e, err := r.Step(msg)
@@ -210,9 +390,23 @@ func (n *Node) Step(ctx context.Context, source uint64, msg *msgs.Msg) error {
 		return err
 	}
 
+	return n.inject(ctx, e)
+}
+
+// inject delivers events to the state machine, outside of the results of
+// the node's work.
+func (n *Node) inject(ctx context.Context, e *statemachine.EventList) error {
+	if n.manualInbox.add(func(workItems *processor.WorkItems) {
+		workItems.ResultEvents().PushBackList(e)
+	}) {
+		return nil
+	}
+
 	select {
 	case n.replicas.eventC <- e:
 		return nil
+	case <-n.stopC:
+		return ErrStopping
 	case <-n.workErrNotifier.ExitStatusC():
 		return n.workErrNotifier.Err()
 	case <-ctx.Done():
@@ -224,7 +418,10 @@ func (n *Node) Client(id uint64) *Client {
 	return &Client{
 		client:          n.clients.Client(id),
 		resultC:         n.clientResultsC,
+		manualInbox:     n.manualInbox,
 		workErrNotifier: n.workErrNotifier,
+		stopC:           n.stopC,
+		tracer:          n.tracer,
 	}
 }
 
@@ -236,13 +433,21 @@ func (n *Node) doWALWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	walResults, err := processor.ProcessWALActions(n.processorConfig.WAL, actions)
-	if err != nil {
+	start := time.Now()
+	var walResults *statemachine.ActionList
+	wal := &resumableWAL{WAL: n.processorConfig.WAL}
+	err := n.perform(StageWAL, exitC, func() (err error) {
+		wal.resume()
+		walResults, err = processor.ProcessWALActions(wal, actions)
 		return errors.WithMessage(err, "could not perform WAL actions")
+	})
+	if err != nil {
+		return err
 	}
+	n.observeStage(StageWAL, start)
 
 	if walResults.Len() == 0 {
-		return nil
+		return n.finished(exitC)
 	}
 
 	select {
@@ -251,7 +456,7 @@ func (n *Node) doWALWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	return nil
+	return n.finished(exitC)
 }
 
 func (n *Node) doClientWork(exitC <-chan struct{}) error {
@@ -262,13 +467,19 @@ func (n *Node) doClientWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	clientResults, err := n.clients.ProcessClientActions(actions)
-	if err != nil {
+	start := time.Now()
+	var clientResults *statemachine.EventList
+	err := n.perform(StageClient, exitC, func() (err error) {
+		clientResults, err = n.ProcessClientActions(actions)
 		return errors.WithMessage(err, "could not perform client actions")
+	})
+	if err != nil {
+		return err
 	}
+	n.observeStage(StageClient, start)
 
 	if clientResults.Len() == 0 {
-		return nil
+		return n.finished(exitC)
 	}
 
 	select {
@@ -276,7 +487,7 @@ func (n *Node) doClientWork(exitC <-chan struct{}) error {
 	case <-exitC:
 		return ErrStopped
 	}
-	return nil
+	return n.finished(exitC)
 }
 
 func (n *Node) doHashWork(exitC <-chan struct{}) error {
@@ -287,10 +498,16 @@ func (n *Node) doHashWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	hashResults, err := processor.ProcessHashActions(n.processorConfig.Hasher, actions)
-	if err != nil {
+	start := time.Now()
+	var hashResults *statemachine.EventList
+	err := n.perform(StageHash, exitC, func() (err error) {
+		hashResults, err = processor.ProcessHashActions(n.processorConfig.Hasher, actions)
 		return errors.WithMessage(err, "could not perform hash actions")
+	})
+	if err != nil {
+		return err
 	}
+	n.observeStage(StageHash, start)
 
 	select {
 	case n.hashResultsC <- hashResults:
@@ -298,7 +515,7 @@ func (n *Node) doHashWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	return nil
+	return n.finished(exitC)
 }
 
 func (n *Node) doNetWork(exitC <-chan struct{}) error {
@@ -309,10 +526,16 @@ func (n *Node) doNetWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	netResults, err := processor.ProcessNetActions(n.ID, n.processorConfig.Link, actions)
-	if err != nil {
+	start := time.Now()
+	var netResults *statemachine.EventList
+	err := n.perform(StageNet, exitC, func() (err error) {
+		netResults, err = processor.ProcessNetActions(n.ID, n.processorConfig.Link, actions)
 		return errors.WithMessage(err, "could not perform net actions")
+	})
+	if err != nil {
+		return err
 	}
+	n.observeStage(StageNet, start)
 
 	select {
 	case n.netResultsC <- netResults:
@@ -320,7 +543,7 @@ func (n *Node) doNetWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	return nil
+	return n.finished(exitC)
 }
 
 func (n *Node) doAppWork(exitC <-chan struct{}) error {
@@ -331,10 +554,18 @@ func (n *Node) doAppWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	appResults, err := processor.ProcessAppActions(n.processorConfig.App, actions)
-	if err != nil {
+	start := time.Now()
+	var appResults *statemachine.EventList
+	app := &resumableApp{App: n.processorConfig.App}
+	err := n.perform(StageApp, exitC, func() (err error) {
+		app.resume()
+		appResults, err = n.processAppActions(app, actions)
 		return errors.WithMessage(err, "could not perform app actions")
+	})
+	if err != nil {
+		return err
 	}
+	n.observeStage(StageApp, start)
 
 	select {
 	case n.appResultsC <- appResults:
@@ -342,7 +573,7 @@ func (n *Node) doAppWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	return nil
+	return n.finished(exitC)
 }
 
 func (n *Node) doReqStoreWork(exitC <-chan struct{}) error {
@@ -353,10 +584,16 @@ func (n *Node) doReqStoreWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	reqStoreResults, err := processor.ProcessReqStoreEvents(n.processorConfig.RequestStore, events)
-	if err != nil {
+	start := time.Now()
+	var reqStoreResults *statemachine.EventList
+	err := n.perform(StageReqStore, exitC, func() (err error) {
+		reqStoreResults, err = processor.ProcessReqStoreEvents(n.processorConfig.RequestStore, events)
 		return errors.WithMessage(err, "could not perform reqstore actions")
+	})
+	if err != nil {
+		return err
 	}
+	n.observeStage(StageReqStore, start)
 
 	select {
 	case n.reqStoreResultsC <- reqStoreResults:
@@ -364,7 +601,7 @@ func (n *Node) doReqStoreWork(exitC <-chan struct{}) error {
 		return ErrStopped
 	}
 
-	return nil
+	return n.finished(exitC)
 }
 
 func (n *Node) doStateMachineWork(exitC <-chan struct{}) (err error) {
@@ -378,27 +615,92 @@ func (n *Node) doStateMachineWork(exitC <-chan struct{}) (err error) {
 	var events *statemachine.EventList
 	select {
 	case events = <-n.resultEventsC:
+	case statusC := <-n.statusC:
+		s, err := n.stateMachine.Status()
+		if err != nil {
+			return err
+		}
+		statusC <- s
+		return nil
 	case <-exitC:
 		return ErrStopped
 	}
 
-	actions, err := processor.ProcessStateMachineEvents(n.stateMachine, n.processorConfig.Interceptor, events)
+	start := time.Now()
+	actions, err := n.processStateMachineEvents(events)
 	if err != nil {
 		return err
 	}
+	n.observeStage(StageStateMachine, start)
+	n.observeStateMachine()
+
+	if err := n.statusWatchers.notify(n.stateMachine); err != nil {
+		return err
+	}
 
 	if actions.Len() == 0 {
-		return nil
+		return n.finished(exitC)
 	}
 
 	select {
 	case n.resultResultsC <- actions:
-		n.processorConfig.Interceptor.Intercept(statemachine.EventActionsReceived())
+		if n.processorConfig.Interceptor != nil {
+			processor.InterceptActionsReceived(n.processorConfig.Interceptor, &statemachine.ActionList{})
+		}
 	case <-exitC:
 		return ErrStopped
 	}
 
-	return nil
+	return n.finished(exitC)
+}
+
+// ProcessClientActions performs the given client actions against the node's
+// clients.  It is invoked by the node's processing, but is exposed for callers
+// which drive the node manually.
+func (n *Node) ProcessClientActions(actions *statemachine.ActionList) (*statemachine.EventList, error) {
+	return n.clients.ProcessClientActions(actions)
+}
+
+// ProcessAppActions performs the given app actions against the configured app,
+// then notifies any clients waiting on the applied commits.  It is invoked by
+// the node's processing, but is exposed for callers which drive the node manually.
+func (n *Node) ProcessAppActions(actions *statemachine.ActionList) (*statemachine.EventList, error) {
+	return n.processAppActions(n.processorConfig.App, actions)
+}
+
+func (n *Node) processAppActions(app processor.App, actions *statemachine.ActionList) (*statemachine.EventList, error) {
+	appResults, err := processor.ProcessAppActions(app, actions)
+	if err != nil {
+		return nil, err
+	}
+
+	n.clients.ProcessAppliedCommits(actions)
+	n.observeCommits(actions)
+	if n.tracer != nil {
+		n.tracer.Applied(actions)
+	}
+
+	return appResults, nil
+}
+
+// processStateMachineEvents applies events to the state machine, tracing the
+// requests they and the resulting actions concern.  It must be invoked from
+// the go routine applying events to the state machine.
+func (n *Node) processStateMachineEvents(events *statemachine.EventList) (*statemachine.ActionList, error) {
+	if n.tracer != nil {
+		n.tracer.Events(events)
+	}
+
+	actions, err := processor.ProcessStateMachineEvents(n.stateMachine, n.processorConfig.Interceptor, n.processorConfig.RequestStore, events)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.tracer != nil {
+		n.tracer.Actions(actions)
+	}
+
+	return actions, nil
 }
 
 type workFunc func(exitC <-chan struct{}) error
@@ -420,6 +722,26 @@ type ProcessorConfig struct {
 	WAL          processor.WAL
 	RequestStore processor.RequestStore
 	Interceptor  processor.EventInterceptor
+
+	// MaxClientBytes bounds the bytes of request data each client may hold
+	// before proposals block (or fail with processor.ErrBackpressure).
+	// Zero indicates no limit.
+	MaxClientBytes uint64
+
+	// Metrics optionally receives measurements of the node's operation.
+	Metrics processor.Metrics
+
+	// RateLimits optionally limits the rate at which each client's new requests
+	// are admitted locally, and at which other replicas may forward them.
+	RateLimits *processor.RateLimits
+
+	// FailurePolicies configures the response to errors performing the work
+	// of each stage.  Stages without a policy fail fast, stopping the node.
+	FailurePolicies map[Stage]*FailurePolicy
+
+	// Tracer optionally receives a span for each stage of the pipeline
+	// reached by each request, see tracing.NewJSONLines.
+	Tracer tracing.Tracer
 }
 
 func (n *Node) runtimeParms() *state.EventInitialParameters {
@@ -439,13 +761,11 @@ func (n *Node) ProcessAsNewNode(
 	initialNetworkState *msgs.NetworkState,
 	initialCheckpointValue []byte,
 ) error {
-	events, err := processor.IntializeWALForNewNode(n.processorConfig.WAL, n.runtimeParms(), initialNetworkState, initialCheckpointValue)
-	if err != nil {
+	if err := n.initializeAsNewNode(initialNetworkState, initialCheckpointValue); err != nil {
 		n.workErrNotifier.SetExitStatus(nil, errors.Errorf("state machine was not started"))
 		return err
 	}
 
-	n.workItems.ResultEvents().PushBackList(events)
 	return n.process(exitC, tickC)
 }
 
@@ -453,15 +773,34 @@ func (n *Node) RestartProcessing(
 	exitC <-chan struct{},
 	tickC <-chan time.Time,
 ) error {
+	if err := n.initializeAsRestartedNode(); err != nil {
+		n.workErrNotifier.SetExitStatus(nil, errors.Errorf("state machine was not started"))
+		return err
+	}
+
+	return n.process(exitC, tickC)
+}
+
+func (n *Node) initializeAsNewNode(initialNetworkState *msgs.NetworkState, initialCheckpointValue []byte) error {
+	events, err := processor.IntializeWALForNewNode(n.processorConfig.WAL, n.runtimeParms(), initialNetworkState, initialCheckpointValue)
+	if err != nil {
+		return err
+	}
+
+	n.workItems.ResultEvents().PushBackList(events)
+	return nil
+}
+
+func (n *Node) initializeAsRestartedNode() error {
 	events, err := processor.RecoverWALForExistingNode(n.processorConfig.WAL, n.runtimeParms())
 	if err != nil {
-		n.workErrNotifier.SetExitStatus(nil, errors.Errorf("state machine was not started"))
 		return err
 	}
 
 	n.workItems.ResultEvents().PushBackList(events)
-	return n.process(exitC, tickC)
+	return nil
 }
+
 func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 	var wg sync.WaitGroup
 	for _, work := range []workFunc{
@@ -484,31 +823,45 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 	var walActionsC, clientActionsC, hashActionsC, netActionsC, appActionsC chan<- *statemachine.ActionList
 	var reqStoreEventsC, resultEventsC chan<- *statemachine.EventList
 
+	// Once stopping, the node accepts no further steps or ticks, and exits
+	// after all queued and in-flight work completes.
+	stepEventsC := n.replicas.eventC
+	stopC := n.stopC
+	var draining bool
+	var inFlight int
+
 	for {
 		select {
 		case resultEventsC <- n.workItems.ResultEvents():
 			n.workItems.ClearResultEvents()
 			resultEventsC = nil
+			inFlight++
 		case walActionsC <- n.workItems.WALActions():
 			n.workItems.ClearWALActions()
 			walActionsC = nil
+			inFlight++
 		case walResultsC := <-n.walResultsC:
 			n.workItems.AddWALResults(walResultsC)
 		case clientActionsC <- n.workItems.ClientActions():
 			n.workItems.ClearClientActions()
 			clientActionsC = nil
+			inFlight++
 		case hashActionsC <- n.workItems.HashActions():
 			n.workItems.ClearHashActions()
 			hashActionsC = nil
+			inFlight++
 		case netActionsC <- n.workItems.NetActions():
 			n.workItems.ClearNetActions()
 			netActionsC = nil
+			inFlight++
 		case appActionsC <- n.workItems.AppActions():
 			n.workItems.ClearAppActions()
 			appActionsC = nil
+			inFlight++
 		case reqStoreEventsC <- n.workItems.ReqStoreEvents():
 			n.workItems.ClearReqStoreEvents()
 			reqStoreEventsC = nil
+			inFlight++
 		case clientResults := <-n.clientResultsC:
 			n.workItems.AddClientResults(clientResults)
 		case hashResults := <-n.hashResultsC:
@@ -521,7 +874,9 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 			n.workItems.AddReqStoreResults(reqStoreResults)
 		case actions := <-n.resultResultsC:
 			n.workItems.AddStateMachineResults(actions)
-		case stepEvents := <-n.replicas.eventC:
+		case <-n.workDoneC:
+			inFlight--
+		case stepEvents := <-stepEventsC:
 			// TODO, once request forwarding works, we'll
 			// need to split this into the req store component
 			// and 'other' that goes into the result events.
@@ -532,6 +887,15 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 			n.workItems.ResultEvents().TickElapsed()
 		case <-exitC:
 			n.workErrNotifier.Fail(ErrStopped)
+		case <-stopC:
+			draining = true
+			stopC = nil
+			stepEventsC = nil
+			tickC = nil
+		}
+
+		if draining && inFlight == 0 && n.workItems.Len() == 0 {
+			n.workErrNotifier.Fail(ErrStopped)
 		}
 
 		if resultEventsC == nil && n.workItems.ResultEvents().Len() > 0 {
@@ -561,6 +925,8 @@ func (n *Node) process(exitC <-chan struct{}, tickC <-chan time.Time) error {
 		if reqStoreEventsC == nil && n.workItems.ReqStoreEvents().Len() > 0 {
 			reqStoreEventsC = n.reqStoreEventsC
 		}
+
+		n.observeWorkItems(n.workItems)
 	}
 }
 
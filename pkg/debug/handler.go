@@ -0,0 +1,237 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package debug provides an http.Handler for inspecting a running node, in the
+// spirit of net/http/pprof.
+package debug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+// StatusSource is satisfied by *mirbft.Node.
+type StatusSource interface {
+	Status(ctx context.Context) (*status.StateMachine, error)
+}
+
+// WALSummary describes the contents of the WAL.
+type WALSummary struct {
+	FirstIndex uint64         `json:"first_index"`
+	LastIndex  uint64         `json:"last_index"`
+	Entries    int            `json:"entries"`
+	EntryTypes map[string]int `json:"entry_types"`
+}
+
+// Handler serves debugging information for a node.  It is intended to be mounted
+// under a prefix, for instance:
+//
+//	http.Handle("/debug/mirbft/", http.StripPrefix("/debug/mirbft", handler))
+//
+// and serves the following paths:
+//
+//	/status         the state machine status as JSON
+//	/status/pretty  the state machine status as text
+//	/events         the most recently intercepted state events as JSON, limited by ?n=
+//	/wal            the WAL index range and a count of each entry type as JSON
+//	/clients        the client windows as JSON
+//
+// Any component left nil causes its paths to return 404.
+type Handler struct {
+	Node    StatusSource
+	Ring    *Ring
+	WAL     processor.WAL
+	Timeout time.Duration // The timeout for obtaining the status, defaults to 5 seconds
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch strings.TrimSuffix(r.URL.Path, "/") {
+	case "", "/":
+		h.serveIndex(w)
+	case "/status":
+		h.serveStatus(w, r, false)
+	case "/status/pretty":
+		h.serveStatus(w, r, true)
+	case "/events":
+		h.serveEvents(w, r)
+	case "/wal":
+		h.serveWAL(w, r)
+	case "/clients":
+		h.serveClients(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *Handler) serveIndex(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "status          the state machine status as JSON")
+	fmt.Fprintln(w, "status/pretty   the state machine status as text")
+	fmt.Fprintln(w, "events?n=N      the most recent state events as JSON")
+	fmt.Fprintln(w, "wal             the WAL index range and entry types as JSON")
+	fmt.Fprintln(w, "clients         the client windows as JSON")
+}
+
+func (h *Handler) status(r *http.Request) (*status.StateMachine, error) {
+	timeout := h.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	// Note, the node returns its final status along with the
+	// error if it has stopped, which is still worth displaying.
+	s, err := h.Node.Status(ctx)
+	if s == nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (h *Handler) serveStatus(w http.ResponseWriter, r *http.Request, pretty bool) {
+	if h.Node == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s, err := h.status(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not get status: %s", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	if pretty {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		fmt.Fprint(w, s.Pretty())
+		return
+	}
+
+	writeJSON(w, s)
+}
+
+func (h *Handler) serveClients(w http.ResponseWriter, r *http.Request) {
+	if h.Node == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	s, err := h.status(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not get status: %s", err), http.StatusServiceUnavailable)
+		return
+	}
+
+	writeJSON(w, s.ClientWindows)
+}
+
+func (h *Handler) serveEvents(w http.ResponseWriter, r *http.Request) {
+	if h.Ring == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	entries := h.Ring.Entries()
+	if nStr := r.URL.Query().Get("n"); nStr != "" {
+		n, err := strconv.Atoi(nStr)
+		if err != nil || n < 0 {
+			http.Error(w, fmt.Sprintf("invalid value for n: %q", nStr), http.StatusBadRequest)
+			return
+		}
+
+		if n < len(entries) {
+			entries = entries[len(entries)-n:]
+		}
+	}
+
+	type jsonEntry struct {
+		Time  time.Time       `json:"time"`
+		Event json.RawMessage `json:"event"`
+	}
+
+	result := make([]jsonEntry, len(entries))
+	for i, entry := range entries {
+		event, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(entry.Event)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not marshal event: %s", err), http.StatusInternalServerError)
+			return
+		}
+		result[i] = jsonEntry{
+			Time:  entry.Time,
+			Event: event,
+		}
+	}
+
+	writeJSON(w, result)
+}
+
+func (h *Handler) serveWAL(w http.ResponseWriter, r *http.Request) {
+	if h.WAL == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	summary, err := SummarizeWAL(h.WAL)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not read WAL: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, summary)
+}
+
+// SummarizeWAL reads the entire WAL, returning its index range and the number of
+// entries of each type.
+func SummarizeWAL(wal processor.WAL) (*WALSummary, error) {
+	summary := &WALSummary{
+		EntryTypes: map[string]int{},
+	}
+
+	err := wal.LoadAll(func(index uint64, p *msgs.Persistent) {
+		if summary.Entries == 0 {
+			summary.FirstIndex = index
+		}
+		summary.LastIndex = index
+		summary.Entries++
+		summary.EntryTypes[persistentTypeName(p)]++
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// persistentTypeName returns the name of the entry type, for instance "QEntry".
+func persistentTypeName(p *msgs.Persistent) string {
+	name := fmt.Sprintf("%T", p.Type)
+	return name[strings.LastIndex(name, "_")+1:]
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not marshal JSON: %s", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+	w.Write([]byte("\n"))
+}
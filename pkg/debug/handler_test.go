@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/debug"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+type fakeNode struct {
+	status *status.StateMachine
+}
+
+func (fn *fakeNode) Status(ctx context.Context) (*status.StateMachine, error) {
+	return fn.status, nil
+}
+
+type fakeWAL struct {
+	entries map[uint64]*msgs.Persistent
+}
+
+func (fw *fakeWAL) Write(index uint64, entry *msgs.Persistent) error { return nil }
+func (fw *fakeWAL) Truncate(index uint64) error                      { return nil }
+func (fw *fakeWAL) Sync() error                                      { return nil }
+func (fw *fakeWAL) LoadAll(forEach func(index uint64, p *msgs.Persistent)) error {
+	for i := uint64(3); i <= 5; i++ {
+		forEach(i, fw.entries[i])
+	}
+	return nil
+}
+
+var _ = Describe("Handler", func() {
+	var (
+		ring   *debug.Ring
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		ring = debug.NewRing(2, nil)
+		handler := &debug.Handler{
+			Node: &fakeNode{
+				status: &status.StateMachine{
+					NodeID:        7,
+					LowWatermark:  10,
+					HighWatermark: 20,
+					EpochTracker: &status.EpochTracker{
+						ActiveEpoch: &status.EpochTarget{Number: 3},
+					},
+					Buckets: []*status.Bucket{
+						{ID: 0, Leader: true},
+					},
+					ClientWindows: []*status.ClientTracker{
+						{ClientID: 1, LowWatermark: 4, HighWatermark: 104},
+					},
+				},
+			},
+			Ring: ring,
+			WAL: &fakeWAL{
+				entries: map[uint64]*msgs.Persistent{
+					3: {Type: &msgs.Persistent_CEntry{CEntry: &msgs.CEntry{}}},
+					4: {Type: &msgs.Persistent_QEntry{QEntry: &msgs.QEntry{}}},
+					5: {Type: &msgs.Persistent_QEntry{QEntry: &msgs.QEntry{}}},
+				},
+			},
+		}
+		server = httptest.NewServer(http.StripPrefix("/debug", handler))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	get := func(path string) string {
+		resp, err := http.Get(server.URL + path)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	It("serves the status as JSON and text", func() {
+		s := &status.StateMachine{}
+		Expect(json.Unmarshal([]byte(get("/debug/status")), s)).To(Succeed())
+		Expect(s.NodeID).To(Equal(uint64(7)))
+
+		Expect(get("/debug/status/pretty")).To(ContainSubstring("NodeID=7, LowWatermark=10, HighWatermark=20, Epoch=3"))
+	})
+
+	It("serves the client windows", func() {
+		var clients []*status.ClientTracker
+		Expect(json.Unmarshal([]byte(get("/debug/clients")), &clients)).To(Succeed())
+		Expect(clients).To(HaveLen(1))
+		Expect(clients[0].HighWatermark).To(Equal(uint64(104)))
+	})
+
+	It("serves the most recent events", func() {
+		for i := 0; i < 3; i++ {
+			Expect(ring.Intercept(statemachine.EventTickElapsed())).To(Succeed())
+		}
+		Expect(ring.Intercept(statemachine.EventActionsReceived())).To(Succeed())
+
+		var events []map[string]interface{}
+		Expect(json.Unmarshal([]byte(get("/debug/events")), &events)).To(Succeed())
+		Expect(events).To(HaveLen(2))
+		Expect(events[0]["event"]).To(HaveKey("tick_elapsed"))
+		Expect(events[1]["event"]).To(HaveKey("actions_received"))
+
+		Expect(json.Unmarshal([]byte(get("/debug/events?n=1")), &events)).To(Succeed())
+		Expect(events).To(HaveLen(1))
+	})
+
+	It("serves a WAL summary", func() {
+		summary := &debug.WALSummary{}
+		Expect(json.Unmarshal([]byte(get("/debug/wal")), summary)).To(Succeed())
+		Expect(summary).To(Equal(&debug.WALSummary{
+			FirstIndex: 3,
+			LastIndex:  5,
+			Entries:    3,
+			EntryTypes: map[string]int{
+				"CEntry": 1,
+				"QEntry": 2,
+			},
+		}))
+	})
+})
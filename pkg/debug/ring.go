@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package debug
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// RingEntry is a state event retained by a Ring, along with the time
+// at which it was intercepted.
+type RingEntry struct {
+	Time  time.Time
+	Event *state.Event
+}
+
+// Ring is an EventInterceptor which retains the most recent state events
+// in memory.  It may optionally pass each event on to another interceptor,
+// such as an eventlog.Recorder.
+type Ring struct {
+	next processor.EventInterceptor
+
+	mutex   sync.Mutex
+	entries []RingEntry
+	head    int
+	full    bool
+}
+
+// NewRing creates a ring retaining the last size events.  If next is non-nil
+// each event is passed to it after being retained.
+func NewRing(size int, next processor.EventInterceptor) *Ring {
+	if size <= 0 {
+		size = 1
+	}
+
+	return &Ring{
+		next:    next,
+		entries: make([]RingEntry, size),
+	}
+}
+
+func (r *Ring) Intercept(event *state.Event) error {
+	r.retain(event)
+
+	if r.next == nil {
+		return nil
+	}
+
+	return r.next.Intercept(event)
+}
+
+// InterceptActions retains the ActionsReceived event, passing the actions on
+// to the next interceptor if it records them.
+func (r *Ring) InterceptActions(event *state.Event, actions *statemachine.ActionList) error {
+	r.retain(event)
+
+	if r.next == nil {
+		return nil
+	}
+
+	if ai, ok := r.next.(processor.ActionsInterceptor); ok {
+		return ai.InterceptActions(event, actions)
+	}
+
+	return r.next.Intercept(event)
+}
+
+// InterceptRequestData retains the RequestPersisted event, passing the
+// request data on to the next interceptor if it records it.
+func (r *Ring) InterceptRequestData(event *state.Event, data func() ([]byte, error)) error {
+	r.retain(event)
+
+	if r.next == nil {
+		return nil
+	}
+
+	if rdi, ok := r.next.(processor.RequestDataInterceptor); ok {
+		return rdi.InterceptRequestData(event, data)
+	}
+
+	return r.next.Intercept(event)
+}
+
+func (r *Ring) retain(event *state.Event) {
+	r.mutex.Lock()
+	r.entries[r.head] = RingEntry{
+		Time:  time.Now(),
+		Event: event,
+	}
+	r.head = (r.head + 1) % len(r.entries)
+	if r.head == 0 {
+		r.full = true
+	}
+	r.mutex.Unlock()
+}
+
+// Entries returns the retained events, oldest first.
+func (r *Ring) Entries() []RingEntry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if !r.full {
+		return append([]RingEntry{}, r.entries[:r.head]...)
+	}
+
+	result := make([]RingEntry, 0, len(r.entries))
+	result = append(result, r.entries[r.head:]...)
+	return append(result, r.entries[:r.head]...)
+}
@@ -21,6 +21,7 @@ import (
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 )
 
 type RecorderOpt interface{}
@@ -32,11 +33,19 @@ type timeSourceOpt func() int64
 // granularity of the timestamps, or picking some externally
 // supplied sync point when trying to synchronize logs.
 // The default time source will timestamp with the time, in
-// milliseconds since the interceptor was created.
+// milliseconds since the interceptor was created.  Logs to be
+// merged should share a time source, such as WallClock.
 func TimeSourceOpt(source func() int64) RecorderOpt {
 	return timeSourceOpt(source)
 }
 
+// WallClock returns the wall clock time, in milliseconds since the
+// Unix epoch.  It may be supplied to TimeSourceOpt, so that the logs
+// of different nodes may be merged.
+func WallClock() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
+
 type retainRequestDataOpt struct{}
 
 // RetainRequestDataOpt indicates that the full request data should be
@@ -51,6 +60,16 @@ func RetainRequestDataOpt() RecorderOpt {
 	return retainRequestDataOpt{}
 }
 
+type recordActionsOpt struct{}
+
+// RecordActionsOpt indicates that the actions produced by the state machine
+// should be recorded along with each ActionsReceived event.  This roughly
+// doubles the size of the log, but allows a replay to detect where the
+// replayed state machine diverges from the recorded one.
+func RecordActionsOpt() RecorderOpt {
+	return recordActionsOpt{}
+}
+
 type compressionLevelOpt int
 
 // DefaultCompressionLevel is used for event capture when not overridden.
@@ -86,6 +105,7 @@ type Recorder struct {
 	timeSource        func() int64
 	compressionLevel  int
 	retainRequestData bool
+	recordActions     bool
 	eventC            chan eventTime
 	doneC             chan struct{}
 	exitC             chan struct{}
@@ -114,6 +134,8 @@ func NewRecorder(nodeID uint64, dest io.Writer, opts ...RecorderOpt) *Recorder {
 			i.timeSource = v
 		case retainRequestDataOpt:
 			i.retainRequestData = true
+		case recordActionsOpt:
+			i.recordActions = true
 		case compressionLevelOpt:
 			i.compressionLevel = int(v)
 		case bufferSizeOpt:
@@ -127,8 +149,10 @@ func NewRecorder(nodeID uint64, dest io.Writer, opts ...RecorderOpt) *Recorder {
 }
 
 type eventTime struct {
-	event *state.Event
-	time  int64
+	event       *state.Event
+	actions     *statemachine.ActionList
+	requestData []byte
+	time        int64
 }
 
 // Intercept takes an event and enqueues it into the event buffer.
@@ -136,11 +160,48 @@ type eventTime struct {
 // to the output stream has completed (successfully or otherwise), Intercept
 // returns an error.
 func (i *Recorder) Intercept(event *state.Event) error {
-	select {
-	case i.eventC <- eventTime{
+	return i.enqueue(eventTime{
 		event: event,
 		time:  i.timeSource(),
-	}:
+	})
+}
+
+// InterceptActions is invoked in place of Intercept for ActionsReceived
+// events, and records the actions if configured via RecordActionsOpt.
+func (i *Recorder) InterceptActions(event *state.Event, actions *statemachine.ActionList) error {
+	if !i.recordActions {
+		return i.Intercept(event)
+	}
+
+	return i.enqueue(eventTime{
+		event:   event,
+		actions: actions,
+		time:    i.timeSource(),
+	})
+}
+
+// InterceptRequestData is invoked in place of Intercept for RequestPersisted
+// events, and records the request data if configured via RetainRequestDataOpt.
+func (i *Recorder) InterceptRequestData(event *state.Event, data func() ([]byte, error)) error {
+	if !i.retainRequestData {
+		return i.Intercept(event)
+	}
+
+	requestData, err := data()
+	if err != nil {
+		return errors.WithMessage(err, "could not load request data")
+	}
+
+	return i.enqueue(eventTime{
+		event:       event,
+		requestData: requestData,
+		time:        i.timeSource(),
+	})
+}
+
+func (i *Recorder) enqueue(et eventTime) error {
+	select {
+	case i.eventC <- et:
 		return nil
 	case <-i.exitC:
 		i.exitErrMutex.Lock()
@@ -181,9 +242,11 @@ func (i *Recorder) run(dest io.Writer) (exitErr error) {
 
 	write := func(eventTime eventTime) error {
 		return WriteRecordedEvent(gzWriter, &recording.Event{
-			NodeId:     i.nodeID,
-			Time:       eventTime.time,
-			StateEvent: eventTime.event,
+			NodeId:      i.nodeID,
+			Time:        eventTime.time,
+			StateEvent:  eventTime.event,
+			Actions:     RecordedActions(eventTime.actions),
+			RequestData: eventTime.requestData,
 		})
 	}
 
@@ -209,6 +272,23 @@ func (i *Recorder) run(dest io.Writer) (exitErr error) {
 	}
 }
 
+// RecordedActions converts an action list to its recorded form.  A nil list
+// is recorded as nil, indicating the actions were not recorded.
+func RecordedActions(actions *statemachine.ActionList) *recording.ActionList {
+	if actions == nil {
+		return nil
+	}
+
+	result := &recording.ActionList{
+		List: make([]*state.Action, 0, actions.Len()),
+	}
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		result.List = append(result.List, action)
+	}
+	return result
+}
+
 func WriteRecordedEvent(writer io.Writer, event *recording.Event) error {
 	return writeSizePrefixedProto(writer, event)
 }
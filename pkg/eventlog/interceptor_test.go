@@ -9,6 +9,7 @@ package eventlog_test
 import (
 	"bytes"
 	"io"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,8 +17,10 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
 )
 
 var tickEvent = &state.Event{
@@ -93,6 +96,94 @@ var _ = Describe("Reader", func() {
 		Expect(err).To(Equal(io.EOF))
 	})
 
+	It("timestamps events from its creation by default", func() {
+		output.Reset()
+		start := time.Now()
+		interceptor := eventlog.NewRecorder(1, output)
+		Expect(interceptor.Intercept(tickEvent)).To(Succeed())
+		Expect(interceptor.Stop()).To(Succeed())
+		elapsed := time.Since(start).Milliseconds()
+
+		reader, err := eventlog.NewReader(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		se, err := reader.ReadEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(se.Time).To(BeNumerically("<=", elapsed))
+	})
+
+	It("timestamps events with the wall clock when configured to", func() {
+		output.Reset()
+		before := eventlog.WallClock()
+		interceptor := eventlog.NewRecorder(1, output, eventlog.TimeSourceOpt(eventlog.WallClock))
+		Expect(interceptor.Intercept(tickEvent)).To(Succeed())
+		Expect(interceptor.Stop()).To(Succeed())
+		after := eventlog.WallClock()
+
+		reader, err := eventlog.NewReader(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		se, err := reader.ReadEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(se.Time).To(BeNumerically(">=", before))
+		Expect(se.Time).To(BeNumerically("<=", after))
+	})
+
+	It("records actions only when configured to", func() {
+		actions := (&statemachine.ActionList{}).Truncate(3)
+
+		output.Reset()
+		interceptor := eventlog.NewRecorder(
+			1,
+			output,
+			eventlog.TimeSourceOpt(func() int64 { return 2 }),
+			eventlog.RecordActionsOpt(),
+		)
+		Expect(interceptor.InterceptActions(statemachine.EventActionsReceived(), actions)).To(Succeed())
+		Expect(interceptor.Intercept(tickEvent)).To(Succeed())
+		Expect(interceptor.Stop()).To(Succeed())
+
+		reader, err := eventlog.NewReader(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		se, err := reader.ReadEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(se.Actions.List).To(HaveLen(1))
+		Expect(proto.Equal(se.Actions.List[0], statemachine.ActionTruncate(3))).To(BeTrue())
+
+		se, err = reader.ReadEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(se.Actions).To(BeNil())
+	})
+
+	It("records request data only when configured to", func() {
+		persisted := (&statemachine.EventList{}).RequestPersisted(&msgs.RequestAck{
+			ClientId: 1,
+			ReqNo:    2,
+			Digest:   []byte("digest"),
+		}).Iterator().Next()
+		data := func() ([]byte, error) {
+			return []byte("data"), nil
+		}
+
+		record := func(opts ...eventlog.RecorderOpt) *recording.Event {
+			output.Reset()
+			interceptor := eventlog.NewRecorder(1, output, opts...)
+			Expect(interceptor.InterceptRequestData(persisted, data)).To(Succeed())
+			Expect(interceptor.Stop()).To(Succeed())
+
+			reader, err := eventlog.NewReader(output)
+			Expect(err).NotTo(HaveOccurred())
+			se, err := reader.ReadEvent()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proto.Equal(se.StateEvent, persisted)).To(BeTrue())
+			return se
+		}
+
+		Expect(record().RequestData).To(BeNil())
+		Expect(record(eventlog.RetainRequestDataOpt()).RequestData).To(Equal([]byte("data")))
+	})
+
 	When("the output is truncated", func() {
 		BeforeEach(func() {
 			output.Truncate(2)
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventlog
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+)
+
+// Merge writes the events of the sources to dest as a single gzip stream
+// ordered by time.  Events of equal time are taken from the earlier source,
+// and the events of each source retain their order.  The time of each event
+// is as recorded, so the recordings to merge should share a time source,
+// for instance with TimeSourceOpt(WallClock).
+func Merge(dest io.Writer, sources ...*Reader) error {
+	heads := make([]*recording.Event, len(sources))
+	advance := func(i int) error {
+		event, err := sources[i].ReadEvent()
+		switch {
+		case err == io.EOF:
+			heads[i] = nil
+		case err != nil:
+			return errors.WithMessagef(err, "could not read source %d", i)
+		default:
+			heads[i] = event
+		}
+		return nil
+	}
+
+	for i := range sources {
+		if err := advance(i); err != nil {
+			return err
+		}
+	}
+
+	gzWriter, err := gzip.NewWriterLevel(dest, DefaultCompressionLevel)
+	if err != nil {
+		return err
+	}
+
+	for {
+		next := -1
+		for i, head := range heads {
+			if head != nil && (next == -1 || head.Time < heads[next].Time) {
+				next = i
+			}
+		}
+
+		if next == -1 {
+			break
+		}
+
+		if err := WriteRecordedEvent(gzWriter, heads[next]); err != nil {
+			return errors.WithMessage(err, "could not write merged event")
+		}
+
+		if err := advance(next); err != nil {
+			return err
+		}
+	}
+
+	return errors.WithMessage(gzWriter.Close(), "could not flush merged events")
+}
+
+// Split writes the events of the source to a gzip stream per node, in the
+// order recorded.  The destination of each node is obtained from dest upon
+// encountering the node's first event.
+func Split(source *Reader, dest func(nodeID uint64) (io.Writer, error)) error {
+	gzWriters := map[uint64]*gzip.Writer{}
+	closeAll := func() error {
+		var closeErr error
+		for nodeID, gzWriter := range gzWriters {
+			if err := gzWriter.Close(); err != nil && closeErr == nil {
+				closeErr = errors.WithMessagef(err, "could not flush events of node %d", nodeID)
+			}
+		}
+		return closeErr
+	}
+
+	for event, err := source.ReadEvent(); err != io.EOF; event, err = source.ReadEvent() {
+		if err != nil {
+			closeAll()
+			return errors.WithMessage(err, "could not read source")
+		}
+
+		gzWriter, ok := gzWriters[event.NodeId]
+		if !ok {
+			w, err := dest(event.NodeId)
+			if err != nil {
+				closeAll()
+				return errors.WithMessagef(err, "could not create destination for node %d", event.NodeId)
+			}
+
+			gzWriter, err = gzip.NewWriterLevel(w, DefaultCompressionLevel)
+			if err != nil {
+				closeAll()
+				return err
+			}
+			gzWriters[event.NodeId] = gzWriter
+		}
+
+		if err := WriteRecordedEvent(gzWriter, event); err != nil {
+			closeAll()
+			return errors.WithMessagef(err, "could not write event of node %d", event.NodeId)
+		}
+	}
+
+	return closeAll()
+}
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package eventlog_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+)
+
+// recorded returns a recording of step events for the node at the given
+// times, with the source of each step identifying its position.
+func recorded(nodeID uint64, times ...int64) []*recording.Event {
+	events := make([]*recording.Event, len(times))
+	for i, time := range times {
+		events[i] = &recording.Event{
+			NodeId: nodeID,
+			Time:   time,
+			StateEvent: &state.Event{
+				Type: &state.Event_Step{
+					Step: &state.EventStep{
+						Source: uint64(i),
+					},
+				},
+			},
+		}
+	}
+	return events
+}
+
+func write(events ...*recording.Event) *bytes.Buffer {
+	output := &bytes.Buffer{}
+	gzWriter := gzip.NewWriter(output)
+	for _, event := range events {
+		Expect(eventlog.WriteRecordedEvent(gzWriter, event)).To(Succeed())
+	}
+	Expect(gzWriter.Close()).To(Succeed())
+	return output
+}
+
+func read(input io.Reader) []*recording.Event {
+	reader, err := eventlog.NewReader(input)
+	Expect(err).NotTo(HaveOccurred())
+
+	var events []*recording.Event
+	for event, err := reader.ReadEvent(); err != io.EOF; event, err = reader.ReadEvent() {
+		Expect(err).NotTo(HaveOccurred())
+		events = append(events, event)
+	}
+	return events
+}
+
+func newReader(input io.Reader) *eventlog.Reader {
+	reader, err := eventlog.NewReader(input)
+	Expect(err).NotTo(HaveOccurred())
+	return reader
+}
+
+var _ = Describe("Merge", func() {
+	var (
+		node1, node2 []*recording.Event
+	)
+
+	BeforeEach(func() {
+		node1 = recorded(1, 1, 3, 3, 5)
+		node2 = recorded(2, 2, 3, 4)
+	})
+
+	It("orders the events by time, keeping the order of each source", func() {
+		output := &bytes.Buffer{}
+		Expect(eventlog.Merge(output, newReader(write(node1...)), newReader(write(node2...)))).To(Succeed())
+
+		merged := read(output)
+		expected := []*recording.Event{node1[0], node2[0], node1[1], node1[2], node2[1], node2[2], node1[3]}
+		Expect(merged).To(HaveLen(len(expected)))
+		for i, event := range merged {
+			Expect(event.NodeId).To(Equal(expected[i].NodeId))
+			Expect(event.Time).To(Equal(expected[i].Time))
+			Expect(event.StateEvent.Type.(*state.Event_Step).Step.Source).To(Equal(expected[i].StateEvent.Type.(*state.Event_Step).Step.Source))
+		}
+	})
+
+	It("merges empty sources", func() {
+		output := &bytes.Buffer{}
+		Expect(eventlog.Merge(output, newReader(write()), newReader(write(node2...)))).To(Succeed())
+		Expect(read(output)).To(HaveLen(3))
+	})
+
+	It("splits a merged recording back into its sources", func() {
+		merged := &bytes.Buffer{}
+		Expect(eventlog.Merge(merged, newReader(write(node1...)), newReader(write(node2...)))).To(Succeed())
+
+		outputs := map[uint64]*bytes.Buffer{}
+		Expect(eventlog.Split(newReader(merged), func(nodeID uint64) (io.Writer, error) {
+			Expect(outputs).NotTo(HaveKey(nodeID))
+			outputs[nodeID] = &bytes.Buffer{}
+			return outputs[nodeID], nil
+		})).To(Succeed())
+
+		Expect(outputs).To(HaveLen(2))
+		for nodeID, events := range map[uint64][]*recording.Event{1: node1, 2: node2} {
+			split := read(outputs[nodeID])
+			Expect(split).To(HaveLen(len(events)))
+			for i, event := range split {
+				Expect(event.NodeId).To(Equal(nodeID))
+				Expect(event.Time).To(Equal(events[i].Time))
+				Expect(event.StateEvent.Type.(*state.Event_Step).Step.Source).To(Equal(uint64(i)))
+			}
+		}
+	})
+
+	When("a source is corrupt", func() {
+		It("returns an error", func() {
+			corrupt := write(node1...)
+			corrupt.Truncate(corrupt.Len() - 4)
+			err := eventlog.Merge(&bytes.Buffer{}, newReader(write(node2...)), newReader(corrupt))
+			Expect(err).To(MatchError(ContainSubstring("could not read source 1")))
+		})
+	})
+})
@@ -0,0 +1,204 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package metrics provides implementations of the processor.Metrics interface.
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the histogram bucket upper bounds (in seconds) used when none
+// are specified, ranging from 100us to 10s.
+var DefaultBuckets = []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10}
+
+type metricType string
+
+const (
+	counterType   metricType = "counter"
+	gaugeType     metricType = "gauge"
+	histogramType metricType = "histogram"
+)
+
+type series struct {
+	labels string
+	value  float64
+
+	// Only used by histograms
+	bucketCounts []uint64
+	count        uint64
+}
+
+type family struct {
+	metricType metricType
+	series     map[string]*series
+}
+
+// Prometheus is a processor.Metrics implementation which accumulates measurements
+// in memory, and serves them over HTTP in the Prometheus text exposition format.
+type Prometheus struct {
+	buckets []float64
+
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+// NewPrometheus creates a new exporter, using the supplied histogram buckets,
+// or DefaultBuckets if none are supplied.
+func NewPrometheus(buckets ...float64) *Prometheus {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+
+	sorted := append([]float64{}, buckets...)
+	sort.Float64s(sorted)
+
+	return &Prometheus{
+		buckets:  sorted,
+		families: map[string]*family{},
+	}
+}
+
+// series must be invoked while holding the mutex.
+func (p *Prometheus) series(name string, mt metricType, labels []string) *series {
+	f, ok := p.families[name]
+	if !ok {
+		f = &family{
+			metricType: mt,
+			series:     map[string]*series{},
+		}
+		p.families[name] = f
+	}
+
+	key := formatLabels(labels)
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{
+			labels: key,
+		}
+		if mt == histogramType {
+			s.bucketCounts = make([]uint64, len(p.buckets))
+		}
+		f.series[key] = s
+	}
+
+	return s
+}
+
+func (p *Prometheus) AddCounter(name string, delta float64, labels ...string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.series(name, counterType, labels).value += delta
+}
+
+func (p *Prometheus) SetGauge(name string, value float64, labels ...string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.series(name, gaugeType, labels).value = value
+}
+
+func (p *Prometheus) ObserveHistogram(name string, value float64, labels ...string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	s := p.series(name, histogramType, labels)
+	s.value += value
+	s.count++
+	for i, bound := range p.buckets {
+		if value <= bound {
+			s.bucketCounts[i]++
+		}
+	}
+}
+
+// WriteTo writes all accumulated metrics in the Prometheus text exposition format.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	names := make([]string, 0, len(p.families))
+	for name := range p.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buffer bytes.Buffer
+	for _, name := range names {
+		f := p.families[name]
+		fmt.Fprintf(&buffer, "# TYPE %s %s\n", name, f.metricType)
+
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.metricType != histogramType {
+				fmt.Fprintf(&buffer, "%s%s %s\n", name, braced(s.labels), formatFloat(s.value))
+				continue
+			}
+
+			for i, bound := range p.buckets {
+				fmt.Fprintf(&buffer, "%s_bucket%s %d\n", name, braced(joinLabels(s.labels, "le="+strconv.Quote(formatFloat(bound)))), s.bucketCounts[i])
+			}
+			fmt.Fprintf(&buffer, "%s_bucket%s %d\n", name, braced(joinLabels(s.labels, `le="+Inf"`)), s.count)
+			fmt.Fprintf(&buffer, "%s_sum%s %s\n", name, braced(s.labels), formatFloat(s.value))
+			fmt.Fprintf(&buffer, "%s_count%s %d\n", name, braced(s.labels), s.count)
+		}
+	}
+
+	return buffer.WriteTo(w)
+}
+
+// ServeHTTP serves the accumulated metrics, suitable for scraping by Prometheus.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	p.WriteTo(w)
+}
+
+// formatLabels renders alternating key value pairs as a Prometheus label set
+// (without the enclosing braces).  A trailing key without a value is ignored.
+func formatLabels(labels []string) string {
+	pairs := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		pairs = append(pairs, labels[i]+"="+strconv.Quote(labels[i+1]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func joinLabels(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func braced(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/metrics"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+)
+
+var _ processor.Metrics = &metrics.Prometheus{}
+
+var _ = Describe("Prometheus", func() {
+	var (
+		prometheus *metrics.Prometheus
+		server     *httptest.Server
+	)
+
+	BeforeEach(func() {
+		prometheus = metrics.NewPrometheus(0.1, 1)
+		server = httptest.NewServer(prometheus)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	scrape := func() string {
+		resp, err := http.Get(server.URL)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		return string(body)
+	}
+
+	It("serves counters, gauges, and histograms in the text format", func() {
+		prometheus.AddCounter(processor.MetricCommittedBatches, 1)
+		prometheus.AddCounter(processor.MetricCommittedBatches, 2)
+		prometheus.SetGauge(processor.MetricWorkItems, 7, "queue", "wal")
+		prometheus.SetGauge(processor.MetricWorkItems, 3, "queue", "net")
+		prometheus.ObserveHistogram(processor.MetricSyncDuration, 0.05, "store", "wal")
+		prometheus.ObserveHistogram(processor.MetricSyncDuration, 0.5, "store", "wal")
+		prometheus.ObserveHistogram(processor.MetricSyncDuration, 2, "store", "wal")
+
+		Expect(scrape()).To(Equal(`# TYPE mirbft_committed_batches_total counter
+mirbft_committed_batches_total 3
+# TYPE mirbft_sync_duration_seconds histogram
+mirbft_sync_duration_seconds_bucket{store="wal",le="0.1"} 1
+mirbft_sync_duration_seconds_bucket{store="wal",le="1"} 2
+mirbft_sync_duration_seconds_bucket{store="wal",le="+Inf"} 3
+mirbft_sync_duration_seconds_sum{store="wal"} 2.55
+mirbft_sync_duration_seconds_count{store="wal"} 3
+# TYPE mirbft_work_items gauge
+mirbft_work_items{queue="net"} 3
+mirbft_work_items{queue="wal"} 7
+`))
+	})
+})
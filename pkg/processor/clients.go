@@ -9,6 +9,7 @@ package processor
 import (
 	"bytes"
 	"container/list"
+	"fmt"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -20,6 +21,47 @@ import (
 
 var ErrClientNotExist error = errors.New("client does not exist")
 
+// ErrBackpressure is returned when a proposal falls beyond the client's
+// request window (its low watermark plus its width), or when admitting it
+// would exceed the bytes a client may hold.  The caller should retry once
+// the client's window advances, see Client.CapacityC.
+var ErrBackpressure error = errors.New("client request window or byte limit exhausted")
+
+// ErrCommitReceiptUnavailable is returned when waiting on a request which
+// committed more than a client width below the client low watermark, and
+// for which the commit details are therefore no longer retained.
+var ErrCommitReceiptUnavailable error = errors.New("request committed, but commit receipt is no longer available")
+
+// CommitReceipt describes where a client request was committed in the log.
+type CommitReceipt struct {
+	SeqNo           uint64 // The sequence number of the committing batch
+	BatchPosition   int    // The index of the request within the committing batch
+	Epoch           uint64 // The epoch in which the batch committed
+	CheckpointSeqNo uint64 // The sequence number of the checkpoint which will include the batch
+}
+
+// CommitConflictError is returned when waiting on a request whose request
+// number committed with a different digest than the one proposed, either
+// because another request was committed, or because the null request was.
+type CommitConflictError struct {
+	ClientID uint64
+	ReqNo    uint64
+	Digest   []byte // The committed digest, empty for the null request
+	Receipt  *CommitReceipt
+}
+
+// Null returns whether the request number was filled by the null request.
+func (e *CommitConflictError) Null() bool {
+	return len(e.Digest) == 0
+}
+
+func (e *CommitConflictError) Error() string {
+	if e.Null() {
+		return fmt.Sprintf("client %d req_no=%d committed as the null request at seq_no=%d", e.ClientID, e.ReqNo, e.Receipt.SeqNo)
+	}
+	return fmt.Sprintf("client %d req_no=%d committed with conflicting digest %x at seq_no=%d", e.ClientID, e.ReqNo, e.Digest, e.Receipt.SeqNo)
+}
+
 func (cs *Clients) Client(clientID uint64) *Client {
 	cs.mutex.Lock()
 	defer cs.mutex.Unlock()
@@ -29,7 +71,7 @@ func (cs *Clients) Client(clientID uint64) *Client {
 
 	c, ok := cs.clients[clientID]
 	if !ok {
-		c = newClient(clientID, cs.Hasher, cs.RequestStore)
+		c = newClient(clientID, cs.Hasher, cs.RequestStore, cs.MaxClientBytes, cs.RateLimiter)
 		cs.clients[clientID] = c
 	}
 	return c
@@ -39,6 +81,15 @@ type Clients struct {
 	Hasher       Hasher
 	RequestStore RequestStore
 
+	// MaxClientBytes is the maximum number of bytes of request data
+	// a client may hold before its proposals are rejected with
+	// ErrBackpressure.  Zero indicates no limit.
+	MaxClientBytes uint64
+
+	// RateLimiter optionally limits the rate at which each client's new
+	// proposals are accepted.
+	RateLimiter *RateLimiter
+
 	mutex   sync.Mutex
 	clients map[uint64]*Client
 }
@@ -82,6 +133,28 @@ func (c *Clients) ProcessClientActions(actions *statemachine.ActionList) (*state
 	return events, nil
 }
 
+// ProcessAppliedCommits notifies any requests waiting on commit of the commits
+// contained in the given actions.  It should be invoked only once the app has
+// applied the actions.
+func (c *Clients) ProcessAppliedCommits(actions *statemachine.ActionList) {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		commit, ok := action.Type.(*state.Action_Commit)
+		if !ok {
+			continue
+		}
+
+		for i, req := range commit.Commit.Batch.Requests {
+			c.Client(req.ClientId).committed(req.ReqNo, req.Digest, &CommitReceipt{
+				SeqNo:           commit.Commit.Batch.SeqNo,
+				BatchPosition:   i,
+				Epoch:           commit.Commit.Epoch,
+				CheckpointSeqNo: commit.Commit.CheckpointSeqNo,
+			})
+		}
+	}
+}
+
 // TODO, client needs to be updated based on the state applied events, to give it a low watermark
 // minimally and to clean up the reqNoMap
 type Client struct {
@@ -89,18 +162,35 @@ type Client struct {
 	hasher       Hasher
 	clientID     uint64
 	nextReqNo    uint64
+	clientState  *msgs.NetworkState_Client
 	requestStore RequestStore
 	requests     *list.List
 	reqNoMap     map[uint64]*list.Element
+	maxBytes     uint64
+	heldBytes    uint64
+	rateLimiter  *RateLimiter
+	capacityC    chan struct{}
+	commits      map[uint64]*committedRequest
+	watches      map[uint64][]*CommitWatch
+}
+
+type committedRequest struct {
+	digest  []byte
+	receipt *CommitReceipt
 }
 
-func newClient(clientID uint64, hasher Hasher, reqStore RequestStore) *Client {
+func newClient(clientID uint64, hasher Hasher, reqStore RequestStore, maxBytes uint64, rateLimiter *RateLimiter) *Client {
 	return &Client{
 		clientID:     clientID,
+		rateLimiter:  rateLimiter,
 		hasher:       hasher,
 		requestStore: reqStore,
 		requests:     list.New(),
 		reqNoMap:     map[uint64]*list.Element{},
+		maxBytes:     maxBytes,
+		capacityC:    make(chan struct{}),
+		commits:      map[uint64]*committedRequest{},
+		watches:      map[uint64][]*CommitWatch{},
 	}
 }
 
@@ -108,13 +198,42 @@ type clientRequest struct {
 	reqNo                 uint64
 	localAllocationDigest []byte
 	remoteCorrectDigests  [][]byte
+	size                  uint64
 }
 
 func (c *Client) stateApplied(state *msgs.NetworkState_Client) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
+	c.clientState = state
+	for reqNo, watches := range c.watches {
+		if reqNo >= state.LowWatermark {
+			continue
+		}
+
+		// The request number committed, but if it did so within a state
+		// transfer, its commit was never applied locally, and so its
+		// receipt is unknown.
+		cr, ok := c.commits[reqNo]
+		for _, cw := range watches {
+			if ok {
+				cw.complete(c.clientID, cr.digest, cr.receipt)
+			} else {
+				cw.fail(ErrCommitReceiptUnavailable)
+			}
+		}
+		delete(c.watches, reqNo)
+	}
+	for reqNo := range c.commits {
+		// Commit receipts are retained for one client width below the
+		// low watermark, so that waiters which race with the checkpoint
+		// may still retrieve them.
+		if reqNo+uint64(state.Width) < state.LowWatermark {
+			delete(c.commits, reqNo)
+		}
+	}
 	for reqNo, el := range c.reqNoMap {
 		if reqNo < state.LowWatermark {
+			c.heldBytes -= el.Value.(*clientRequest).size
 			c.requests.Remove(el)
 			delete(c.reqNoMap, reqNo)
 		}
@@ -122,6 +241,21 @@ func (c *Client) stateApplied(state *msgs.NetworkState_Client) {
 	if c.nextReqNo < state.LowWatermark {
 		c.nextReqNo = state.LowWatermark
 	}
+
+	// The window may have advanced, or bytes been freed, so wake
+	// any proposers blocked on backpressure.
+	close(c.capacityC)
+	c.capacityC = make(chan struct{})
+}
+
+// CapacityC returns a channel which is closed the next time the client's
+// request window advances.  To avoid missing an advance, callers should obtain
+// the channel before proposing, and wait on it if the proposal fails with
+// ErrBackpressure.
+func (c *Client) CapacityC() <-chan struct{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.capacityC
 }
 
 func (c *Client) allocate(reqNo uint64) ([]byte, error) {
@@ -176,6 +310,10 @@ func (c *Client) addCorrectDigest(reqNo uint64, digest []byte) error {
 	return nil
 }
 
+func (c *Client) ID() uint64 {
+	return c.clientID
+}
+
 func (c *Client) NextReqNo() (uint64, error) {
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -187,9 +325,7 @@ func (c *Client) NextReqNo() (uint64, error) {
 }
 
 func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, error) {
-	h := c.hasher.New()
-	h.Write(data)
-	digest := h.Sum(nil)
+	digest := c.digest(data)
 
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
@@ -197,10 +333,239 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 		return nil, ErrClientNotExist
 	}
 
-	if reqNo < c.nextReqNo {
-		return &statemachine.EventList{}, nil
+	return c.propose(reqNo, digest, data)
+}
+
+// ProposeAndWatch proposes the request like Propose, but additionally returns
+// a watch which completes once the request number commits and the committing
+// batch has been applied.  The caller must Cancel the watch if it is abandoned
+// before completion.
+func (c *Client) ProposeAndWatch(reqNo uint64, data []byte) (*statemachine.EventList, *CommitWatch, error) {
+	digest := c.digest(data)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.requests.Len() == 0 {
+		return nil, nil, ErrClientNotExist
+	}
+
+	cw := &CommitWatch{
+		client: c,
+		reqNo:  reqNo,
+		digest: digest,
+		doneC:  make(chan struct{}),
+	}
+
+	if cr, ok := c.commits[reqNo]; ok {
+		cw.complete(c.clientID, cr.digest, cr.receipt)
+		return &statemachine.EventList{}, cw, nil
+	}
+
+	if c.clientState != nil && isCommitted(reqNo, c.clientState) {
+		cw.fail(ErrCommitReceiptUnavailable)
+		return &statemachine.EventList{}, cw, nil
+	}
+
+	events, err := c.propose(reqNo, digest, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.watches[reqNo] = append(c.watches[reqNo], cw)
+
+	return events, cw, nil
+}
+
+// ProposeNull allocates the null request (a request with no data and an
+// empty digest) for the given request number.  A client which has lost
+// its request log may use null requests to flush request numbers it cannot
+// replay, after which the replicas will preferentially acknowledge the null
+// request for that request number.  Unlike ordinary requests, a null request
+// may supersede a request previously proposed for the same request number.
+func (c *Client) ProposeNull(reqNo uint64) (*statemachine.EventList, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.requests.Len() == 0 {
+		return nil, ErrClientNotExist
+	}
+
+	return c.propose(reqNo, nil, nil)
+}
+
+// ProposeNullThrough proposes the null request for every request number from
+// the client's committed low watermark through (and including) reqNo which
+// has not already committed.  Once these null requests commit, the client may
+// resume proposing at reqNo+1.
+func (c *Client) ProposeNullThrough(reqNo uint64) (*statemachine.EventList, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.requests.Len() == 0 || c.clientState == nil {
+		return nil, ErrClientNotExist
+	}
+
+	if reqNo > c.clientState.LowWatermark+uint64(c.clientState.Width) {
+		return nil, ErrBackpressure
+	}
+
+	// The null requests are admitted, and charged to the rate limit, as a
+	// whole, so that none are stored unless all may be.
+	var admitted []uint64
+	for i := c.clientState.LowWatermark; i <= reqNo; i++ {
+		if isCommitted(i, c.clientState) {
+			continue
+		}
+
+		ok, err := c.admit(i, nil, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not propose null request for req_no=%d", i)
+		}
+		if ok {
+			admitted = append(admitted, i)
+		}
+	}
+
+	if err := c.rateLimiter.AllowN(c.clientID, len(admitted)); err != nil {
+		return nil, err
+	}
+
+	events := &statemachine.EventList{}
+	for _, i := range admitted {
+		result, err := c.store(i, nil, nil)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "could not propose null request for req_no=%d", i)
+		}
+		events.PushBackList(result)
+	}
+
+	if c.nextReqNo <= reqNo {
+		c.nextReqNo = reqNo + 1
+	}
+
+	return events, nil
+}
+
+func (c *Client) digest(data []byte) []byte {
+	h := c.hasher.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func (c *Client) committed(reqNo uint64, digest []byte, receipt *CommitReceipt) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.commits[reqNo] = &committedRequest{
+		digest:  digest,
+		receipt: receipt,
+	}
+
+	for _, cw := range c.watches[reqNo] {
+		cw.complete(c.clientID, digest, receipt)
+	}
+	delete(c.watches, reqNo)
+}
+
+func (c *Client) cancelWatch(cw *CommitWatch) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	watches := c.watches[cw.reqNo]
+	for i, w := range watches {
+		if w != cw {
+			continue
+		}
+
+		watches = append(watches[:i], watches[i+1:]...)
+		break
+	}
+
+	if len(watches) == 0 {
+		delete(c.watches, cw.reqNo)
+	} else {
+		c.watches[cw.reqNo] = watches
+	}
+}
+
+// propose must be invoked while holding the client mutex.  A nil digest
+// indicates the null request, which has no data to store.  Only new
+// proposals which are accepted consume a rate limit token.
+func (c *Client) propose(reqNo uint64, digest, data []byte) (*statemachine.EventList, error) {
+	admitted, err := c.admit(reqNo, digest, data)
+	if err != nil || !admitted {
+		return &statemachine.EventList{}, err
+	}
+
+	if err := c.rateLimiter.Allow(c.clientID); err != nil {
+		return nil, err
+	}
+
+	return c.store(reqNo, digest, data)
+}
+
+// admit returns whether the proposal is new and may be stored, or an error
+// if it must be rejected.  It must be invoked while holding the client mutex.
+func (c *Client) admit(reqNo uint64, digest, data []byte) (bool, error) {
+	null := digest == nil
+
+	if reqNo < c.nextReqNo && !null {
+		return false, nil
+	}
+
+	if null && c.clientState != nil && reqNo < c.clientState.LowWatermark {
+		return false, errors.Errorf("cannot propose the null request for req_no=%d below the client low watermark %d", reqNo, c.clientState.LowWatermark)
+	}
+
+	if c.clientState != nil && reqNo > c.clientState.LowWatermark+uint64(c.clientState.Width) {
+		return false, ErrBackpressure
+	}
+
+	var cr *clientRequest
+	if el, ok := c.reqNoMap[reqNo]; ok {
+		cr = el.Value.(*clientRequest)
+	}
+
+	// The window is allocated in advance, so only requests whose data is
+	// already held may bypass the byte limit, those being duplicates,
+	// conflicts, or superseded by the null request, none holding more.
+	held := cr != nil && cr.localAllocationDigest != nil
+
+	if !held && c.maxBytes > 0 && c.heldBytes+uint64(len(data)) > c.maxBytes {
+		if uint64(len(data)) > c.maxBytes {
+			return false, errors.Errorf("request of %d bytes exceeds the client byte limit of %d", len(data), c.maxBytes)
+		}
+		return false, ErrBackpressure
+	}
+
+	if held {
+		if bytes.Equal(cr.localAllocationDigest, digest) {
+			return false, nil
+		}
+
+		if !null {
+			return false, errors.Errorf("cannot store request with digest %x, already stored request with different digest %x", digest, cr.localAllocationDigest)
+		}
 	}
 
+	if cr != nil && len(cr.remoteCorrectDigests) > 0 && !null {
+		found := false
+		for _, rd := range cr.remoteCorrectDigests {
+			if bytes.Equal(rd, digest) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false, errors.New("other known correct digest exist for reqno")
+		}
+	}
+
+	return true, nil
+}
+
+// store persists an admitted proposal.  It must be invoked while holding the
+// client mutex.
+func (c *Client) store(reqNo uint64, digest, data []byte) (*statemachine.EventList, error) {
+	null := digest == nil
+
 	if reqNo == c.nextReqNo {
 		for {
 			c.nextReqNo++
@@ -220,7 +585,6 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 	el, ok := c.reqNoMap[reqNo]
 	previouslyAllocated := ok
 	if !ok {
-		// TODO, limit the distance ahead a client can allocate?
 		el = c.requests.PushBack(&clientRequest{
 			reqNo: reqNo,
 		})
@@ -229,48 +593,99 @@ func (c *Client) Propose(reqNo uint64, data []byte) (*statemachine.EventList, er
 
 	cr := el.Value.(*clientRequest)
 
-	if cr.localAllocationDigest != nil {
-		if bytes.Equal(cr.localAllocationDigest, digest) {
-			return &statemachine.EventList{}, nil
-		}
-
-		return nil, errors.Errorf("cannot store request with digest %x, already stored request with different digest %x", digest, cr.localAllocationDigest)
-	}
-
-	if len(cr.remoteCorrectDigests) > 0 {
-		found := false
-		for _, rd := range cr.remoteCorrectDigests {
-			if bytes.Equal(rd, digest) {
-				found = true
-				break
-			}
-		}
-
-		if !found {
-			return nil, errors.New("other known correct digest exist for reqno")
-		}
-	}
-
 	ack := &msgs.RequestAck{
 		ClientId: c.clientID,
 		ReqNo:    reqNo,
 		Digest:   digest,
 	}
 
-	err := c.requestStore.PutRequest(ack, data)
-	if err != nil {
-		return nil, errors.WithMessage(err, "could not store requests")
+	if !null {
+		err := c.requestStore.PutRequest(ack, data)
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not store requests")
+		}
+	} else {
+		// The null request is marked allocated with a non-nil, empty digest,
+		// so that it may be distinguished from an unallocated request.
+		digest = []byte{}
 	}
 
-	err = c.requestStore.PutAllocation(c.clientID, reqNo, digest)
+	err := c.requestStore.PutAllocation(c.clientID, reqNo, digest)
 	if err != nil {
 		return nil, err
 	}
 	cr.localAllocationDigest = digest
 
+	c.heldBytes -= cr.size
+	cr.size = uint64(len(data))
+	c.heldBytes += cr.size
+
 	if previouslyAllocated {
 		return (&statemachine.EventList{}).RequestPersisted(ack), nil
 	}
 
 	return &statemachine.EventList{}, nil
 }
+
+// isCommitted mirrors the state machine's interpretation of the client
+// committed mask, a bitmask relative to the client low watermark.
+func isCommitted(reqNo uint64, clientState *msgs.NetworkState_Client) bool {
+	if reqNo < clientState.LowWatermark {
+		return true
+	}
+
+	offset := int(reqNo - clientState.LowWatermark)
+	byteIndex := offset / 8
+	if byteIndex >= len(clientState.CommittedMask) {
+		return false
+	}
+
+	return clientState.CommittedMask[byteIndex]&(0x80>>uint(offset%8)) != 0
+}
+
+// CommitWatch tracks the commit of a proposed request.  Once Done() is closed,
+// Result() returns the commit receipt, or an error if the request number was
+// filled by a different request.
+type CommitWatch struct {
+	client  *Client
+	reqNo   uint64
+	digest  []byte
+	doneC   chan struct{}
+	receipt *CommitReceipt
+	err     error
+}
+
+// complete must be invoked while holding the client mutex.
+func (cw *CommitWatch) complete(clientID uint64, digest []byte, receipt *CommitReceipt) {
+	if bytes.Equal(digest, cw.digest) {
+		cw.receipt = receipt
+	} else {
+		cw.err = &CommitConflictError{
+			ClientID: clientID,
+			ReqNo:    cw.reqNo,
+			Digest:   digest,
+			Receipt:  receipt,
+		}
+	}
+	close(cw.doneC)
+}
+
+// fail must be invoked while holding the client mutex.
+func (cw *CommitWatch) fail(err error) {
+	cw.err = err
+	close(cw.doneC)
+}
+
+func (cw *CommitWatch) Done() <-chan struct{} {
+	return cw.doneC
+}
+
+func (cw *CommitWatch) Result() (*CommitReceipt, error) {
+	<-cw.doneC
+	return cw.receipt, cw.err
+}
+
+// Cancel stops tracking the commit, it is safe to invoke after completion.
+func (cw *CommitWatch) Cancel() {
+	cw.client.cancelWatch(cw)
+}
@@ -0,0 +1,380 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor_test
+
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"io/ioutil"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// persistedAcks returns the acks of the RequestPersisted events in the list.
+func persistedAcks(events *statemachine.EventList) []*msgs.RequestAck {
+	var acks []*msgs.RequestAck
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if rp, ok := event.Type.(*state.Event_RequestPersisted); ok {
+			acks = append(acks, rp.RequestPersisted.RequestAck)
+		}
+	}
+	return acks
+}
+
+var _ = Describe("Clients", func() {
+	var (
+		tmpDir   string
+		reqStore *reqstore.Store
+		clients  *processor.Clients
+	)
+
+	// applyState applies the client state, then allocates its window, as
+	// the state machine does.
+	applyState := func(clientState *msgs.NetworkState_Client) *statemachine.EventList {
+		actions := (&statemachine.ActionList{}).StateApplied(0, &msgs.NetworkState{
+			Clients: []*msgs.NetworkState_Client{clientState},
+		})
+		for reqNo := clientState.LowWatermark; reqNo <= clientState.LowWatermark+uint64(clientState.Width); reqNo++ {
+			actions.AllocateRequest(clientState.Id, reqNo)
+		}
+
+		events, err := clients.ProcessClientActions(actions)
+		Expect(err).NotTo(HaveOccurred())
+		return events
+	}
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "processor-test-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		reqStore, err = reqstore.Open(tmpDir)
+		Expect(err).NotTo(HaveOccurred())
+
+		clients = &processor.Clients{
+			Hasher:       crypto.SHA256,
+			RequestStore: reqStore,
+		}
+
+		applyState(&msgs.NetworkState_Client{
+			Id:           1,
+			Width:        10,
+			LowWatermark: 5,
+		})
+	})
+
+	AfterEach(func() {
+		reqStore.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	Describe("Propose", func() {
+		BeforeEach(func() {
+			clients = &processor.Clients{
+				Hasher:         crypto.SHA256,
+				RequestStore:   reqStore,
+				MaxClientBytes: 10,
+			}
+
+			applyState(&msgs.NetworkState_Client{
+				Id:           1,
+				Width:        10,
+				LowWatermark: 5,
+			})
+		})
+
+		It("applies backpressure beyond the window", func() {
+			_, err := clients.Client(1).Propose(15, []byte("a"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = clients.Client(1).Propose(16, []byte("b"))
+			Expect(err).To(Equal(processor.ErrBackpressure))
+		})
+
+		It("applies backpressure once the byte limit is held", func() {
+			client := clients.Client(1)
+			_, err := client.Propose(5, []byte("aaaa"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.Propose(6, []byte("bbbb"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.Propose(7, []byte("cccc"))
+			Expect(err).To(Equal(processor.ErrBackpressure))
+
+			// Proposals holding no more bytes are still accepted.
+			_, err = client.Propose(6, []byte("bbbb"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.ProposeNull(7)
+			Expect(err).NotTo(HaveOccurred())
+
+			capacityC := client.CapacityC()
+			applyState(&msgs.NetworkState_Client{
+				Id:           1,
+				Width:        10,
+				LowWatermark: 7,
+			})
+			Expect(capacityC).To(BeClosed())
+
+			_, err = client.Propose(8, []byte("cccc"))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("rejects requests larger than the byte limit", func() {
+			_, err := clients.Client(1).Propose(5, []byte("01234567890"))
+			Expect(err).To(MatchError("request of 11 bytes exceeds the client byte limit of 10"))
+		})
+	})
+
+	Describe("ProposeNull", func() {
+		It("allocates the null request", func() {
+			events, err := clients.Client(1).ProposeNull(7)
+			Expect(err).NotTo(HaveOccurred())
+			acks := persistedAcks(events)
+			Expect(acks).To(HaveLen(1))
+			Expect(acks[0].ReqNo).To(Equal(uint64(7)))
+			Expect(acks[0].Digest).To(BeEmpty())
+
+			digest, err := reqStore.GetAllocation(1, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).NotTo(BeNil())
+			Expect(digest).To(BeEmpty())
+		})
+
+		It("supersedes a proposed request", func() {
+			_, err := clients.Client(1).Propose(7, []byte("data"))
+			Expect(err).NotTo(HaveOccurred())
+
+			events, err := clients.Client(1).ProposeNull(7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(persistedAcks(events)).To(HaveLen(1))
+
+			digest, err := reqStore.GetAllocation(1, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(Equal([]byte{}))
+		})
+
+		It("remains allocated once the request store is reopened", func() {
+			_, err := clients.Client(1).ProposeNull(7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reqStore.Sync()).To(Succeed())
+			reqStore.Close()
+
+			reqStore, err = reqstore.Open(tmpDir)
+			Expect(err).NotTo(HaveOccurred())
+			clients = &processor.Clients{
+				Hasher:       crypto.SHA256,
+				RequestStore: reqStore,
+			}
+
+			acks := persistedAcks(applyState(&msgs.NetworkState_Client{
+				Id:           1,
+				Width:        10,
+				LowWatermark: 5,
+			}))
+			Expect(acks).To(HaveLen(1))
+			Expect(acks[0].ReqNo).To(Equal(uint64(7)))
+			Expect(acks[0].Digest).To(BeEmpty())
+		})
+
+		It("rejects request numbers below the low watermark", func() {
+			_, err := clients.Client(1).ProposeNull(4)
+			Expect(err).To(MatchError("cannot propose the null request for req_no=4 below the client low watermark 5"))
+
+			digest, err := reqStore.GetAllocation(1, 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(BeNil())
+		})
+	})
+
+	Describe("ProposeNullThrough", func() {
+		BeforeEach(func() {
+			applyState(&msgs.NetworkState_Client{
+				Id:            1,
+				Width:         10,
+				LowWatermark:  5,
+				CommittedMask: []byte{0x40}, // req_no=6 committed
+			})
+		})
+
+		It("proposes the null request for the uncommitted request numbers", func() {
+			events, err := clients.Client(1).ProposeNullThrough(8)
+			Expect(err).NotTo(HaveOccurred())
+
+			var reqNos []uint64
+			for _, ack := range persistedAcks(events) {
+				Expect(ack.Digest).To(BeEmpty())
+				reqNos = append(reqNos, ack.ReqNo)
+			}
+			Expect(reqNos).To(Equal([]uint64{5, 7, 8}))
+
+			digest, err := reqStore.GetAllocation(1, 6)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(BeNil())
+
+			nextReqNo, err := clients.Client(1).NextReqNo()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nextReqNo).To(Equal(uint64(9)))
+		})
+
+		It("rejects request numbers beyond the window", func() {
+			_, err := clients.Client(1).ProposeNullThrough(16)
+			Expect(err).To(Equal(processor.ErrBackpressure))
+		})
+	})
+
+	Describe("RateLimiter", func() {
+		BeforeEach(func() {
+			clients = &processor.Clients{
+				Hasher:       crypto.SHA256,
+				RequestStore: reqStore,
+				RateLimiter: processor.NewRateLimiter(&processor.RateLimits{
+					Default: &processor.RateLimit{Rate: 0, Burst: 2},
+				}, nil),
+			}
+
+			applyState(&msgs.NetworkState_Client{
+				Id:           1,
+				Width:        10,
+				LowWatermark: 5,
+			})
+		})
+
+		It("charges only new proposals", func() {
+			client := clients.Client(1)
+			_, err := client.Propose(5, []byte("a"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.Propose(5, []byte("a"))
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.Propose(6, []byte("b"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.Propose(7, []byte("c"))
+			Expect(err).To(BeAssignableToTypeOf(&processor.RateLimitError{}))
+
+			digest, err := reqStore.GetAllocation(1, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(digest).To(BeNil())
+		})
+
+		It("charges null proposals", func() {
+			client := clients.Client(1)
+			_, err := client.ProposeNull(5)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = client.ProposeNull(6)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.ProposeNull(7)
+			Expect(err).To(BeAssignableToTypeOf(&processor.RateLimitError{}))
+		})
+
+		It("charges the null proposals through a request number all at once", func() {
+			client := clients.Client(1)
+			_, err := client.Propose(5, []byte("a"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = client.ProposeNullThrough(7)
+			Expect(err).To(BeAssignableToTypeOf(&processor.RateLimitError{}))
+			for _, reqNo := range []uint64{6, 7} {
+				digest, err := reqStore.GetAllocation(1, reqNo)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(digest).To(BeNil())
+			}
+
+			events, err := client.ProposeNullThrough(5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(persistedAcks(events)).To(HaveLen(1))
+		})
+	})
+
+	Describe("ProposeAndWatch", func() {
+		var (
+			data []byte
+			ack  *msgs.RequestAck
+		)
+
+		BeforeEach(func() {
+			data = []byte("data")
+			h := crypto.SHA256.New()
+			h.Write(data)
+			ack = &msgs.RequestAck{
+				ClientId: 1,
+				ReqNo:    7,
+				Digest:   h.Sum(nil),
+			}
+		})
+
+		commit := func(acks ...*msgs.RequestAck) {
+			clients.ProcessAppliedCommits((&statemachine.ActionList{}).Commit(&msgs.QEntry{
+				SeqNo:    3,
+				Requests: acks,
+			}, 1, 10))
+		}
+
+		It("completes with the receipt once the commit is applied", func() {
+			_, watch, err := clients.Client(1).ProposeAndWatch(7, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(watch.Done()).NotTo(BeClosed())
+
+			commit(&msgs.RequestAck{ClientId: 1, ReqNo: 6}, ack)
+			Expect(watch.Done()).To(BeClosed())
+			Expect(watch.Result()).To(Equal(&processor.CommitReceipt{
+				SeqNo:           3,
+				BatchPosition:   1,
+				Epoch:           1,
+				CheckpointSeqNo: 10,
+			}))
+		})
+
+		It("completes every watch of a duplicate proposal", func() {
+			_, watch1, err := clients.Client(1).ProposeAndWatch(7, data)
+			Expect(err).NotTo(HaveOccurred())
+			events, watch2, err := clients.Client(1).ProposeAndWatch(7, data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events.Len()).To(Equal(0))
+
+			commit(ack)
+			for _, watch := range []*processor.CommitWatch{watch1, watch2} {
+				receipt, err := watch.Result()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(receipt.SeqNo).To(Equal(uint64(3)))
+			}
+		})
+
+		It("completes with a conflict if another request commits", func() {
+			_, watch, err := clients.Client(1).ProposeAndWatch(7, data)
+			Expect(err).NotTo(HaveOccurred())
+
+			commit(&msgs.RequestAck{ClientId: 1, ReqNo: 7})
+			_, err = watch.Result()
+			Expect(err).To(BeAssignableToTypeOf(&processor.CommitConflictError{}))
+			Expect(err.(*processor.CommitConflictError).Null()).To(BeTrue())
+		})
+
+		It("completes once a state transfer moves the low watermark past the request", func() {
+			_, watch, err := clients.Client(1).ProposeAndWatch(7, data)
+			Expect(err).NotTo(HaveOccurred())
+
+			applyState(&msgs.NetworkState_Client{
+				Id:           1,
+				Width:        10,
+				LowWatermark: 8,
+			})
+			Expect(watch.Done()).To(BeClosed())
+			_, err = watch.Result()
+			Expect(err).To(Equal(processor.ErrCommitReceiptUnavailable))
+		})
+	})
+})
@@ -0,0 +1,61 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+// The names of the metrics reported by the processor.  Labels are
+// noted where applicable.
+const (
+	// MetricWorkItems is a gauge of the outstanding work items in each
+	// processing queue, labeled by "queue".
+	MetricWorkItems = "mirbft_work_items"
+
+	// MetricStageDuration is a histogram of the seconds spent performing
+	// each batch of work, labeled by "stage".
+	MetricStageDuration = "mirbft_stage_duration_seconds"
+
+	// MetricSyncDuration is a histogram of the seconds spent syncing
+	// persisted state to disk, labeled by "store" (either "wal" or "reqstore").
+	MetricSyncDuration = "mirbft_sync_duration_seconds"
+
+	// MetricCommittedBatches is a counter of the batches applied.
+	MetricCommittedBatches = "mirbft_committed_batches_total"
+
+	// MetricCommittedRequests is a counter of the requests applied.
+	MetricCommittedRequests = "mirbft_committed_requests_total"
+
+	// MetricEpoch is a gauge of the current epoch number.
+	MetricEpoch = "mirbft_epoch"
+
+	// MetricEpochChanges is a counter of the epoch changes observed.
+	MetricEpochChanges = "mirbft_epoch_changes_total"
+
+	// MetricBufferedBytes is a gauge of the bytes of messages buffered
+	// from each node, labeled by "node".
+	MetricBufferedBytes = "mirbft_buffered_msg_bytes"
+
+	// MetricRateLimited is a counter of client requests rejected by the
+	// rate limiter, labeled by "client".
+	MetricRateLimited = "mirbft_rate_limited_requests_total"
+)
+
+// Metrics receives measurements of the operation of a node.  Labels are
+// supplied as alternating key value pairs.  Implementations must be safe
+// for concurrent use.
+type Metrics interface {
+	AddCounter(name string, delta float64, labels ...string)
+	SetGauge(name string, value float64, labels ...string)
+	ObserveHistogram(name string, value float64, labels ...string)
+}
+
+// NopMetrics discards all measurements.
+type NopMetrics struct{}
+
+func (NopMetrics) AddCounter(string, float64, ...string) {}
+
+func (NopMetrics) SetGauge(string, float64, ...string) {}
+
+func (NopMetrics) ObserveHistogram(string, float64, ...string) {}
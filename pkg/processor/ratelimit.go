@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimit configures a token bucket which admits Rate requests per
+// second on average, with bursts of up to Burst requests.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimits configures the rate limits applied to client requests.  A client
+// is limited by its entry in Clients if one exists, otherwise by the limit for
+// its class (as assigned by ClientClasses) if one exists, otherwise by Default.
+// All clients assigned to a class share a single bucket.  A nil limit indicates
+// that the client is not limited.
+type RateLimits struct {
+	Default       *RateLimit
+	Clients       map[uint64]*RateLimit
+	Classes       map[string]*RateLimit
+	ClientClasses map[uint64]string
+}
+
+// RateLimitError is returned when a client request is rejected by the rate limiter.
+type RateLimitError struct {
+	ClientID   uint64
+	Class      string        // The client's class, if the class limit was applied
+	RetryAfter time.Duration // The time until the next request would be admitted
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Class != "" {
+		return fmt.Sprintf("client %d rate limited by class %q, retry after %v", e.ClientID, e.Class, e.RetryAfter)
+	}
+	return fmt.Sprintf("client %d rate limited, retry after %v", e.ClientID, e.RetryAfter)
+}
+
+// RateLimiter applies RateLimits to client requests.  A nil RateLimiter admits
+// every request.
+type RateLimiter struct {
+	limits  *RateLimits
+	metrics Metrics
+	now     func() time.Time
+
+	mutex    sync.Mutex
+	buckets  map[string]*tokenBucket
+	rejected map[uint64]uint64
+}
+
+// NewRateLimiter creates a rate limiter for the given limits, reporting
+// rejections to metrics.  If limits is nil, the returned limiter is nil.
+func NewRateLimiter(limits *RateLimits, metrics Metrics) *RateLimiter {
+	if limits == nil {
+		return nil
+	}
+
+	if metrics == nil {
+		metrics = NopMetrics{}
+	}
+
+	return &RateLimiter{
+		limits:   limits,
+		metrics:  metrics,
+		now:      time.Now,
+		buckets:  map[string]*tokenBucket{},
+		rejected: map[uint64]uint64{},
+	}
+}
+
+// Allow consumes a token for the given client, returning a *RateLimitError
+// if no token is available.
+func (rl *RateLimiter) Allow(clientID uint64) error {
+	return rl.AllowN(clientID, 1)
+}
+
+// AllowN consumes n tokens for the given client, or none, returning a
+// *RateLimitError if fewer than n are available.  Requests for more tokens
+// than the burst are never admitted.
+func (rl *RateLimiter) AllowN(clientID uint64, n int) error {
+	return rl.allow("", clientID, n)
+}
+
+// AllowForward consumes a token for one of the client's requests forwarded by
+// the given replica.  As each replica forwards each request at most once,
+// forwarded requests are limited per replica, at the client's limit.
+func (rl *RateLimiter) AllowForward(source, clientID uint64) error {
+	return rl.allow(fmt.Sprintf("forward/%d/", source), clientID, 1)
+}
+
+// allow consumes n tokens from the client's bucket within the given scope.
+func (rl *RateLimiter) allow(scope string, clientID uint64, n int) error {
+	if rl == nil || n == 0 {
+		return nil
+	}
+
+	var key, class string
+	limit, ok := rl.limits.Clients[clientID]
+	if ok {
+		key = fmt.Sprintf("client/%d", clientID)
+	} else if class, ok = rl.limits.ClientClasses[clientID]; ok && rl.limits.Classes[class] != nil {
+		limit = rl.limits.Classes[class]
+		key = "class/" + class
+	} else {
+		class = ""
+		limit = rl.limits.Default
+		key = fmt.Sprintf("client/%d", clientID)
+	}
+
+	if limit == nil {
+		return nil
+	}
+
+	key = scope + key
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	bucket, ok := rl.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{
+			rate:   limit.Rate,
+			burst:  float64(limit.Burst),
+			tokens: float64(limit.Burst),
+			last:   rl.now(),
+		}
+		rl.buckets[key] = bucket
+	}
+
+	retryAfter, ok := bucket.take(rl.now(), float64(n))
+	if ok {
+		return nil
+	}
+
+	rl.rejected[clientID]++
+	rl.metrics.AddCounter(MetricRateLimited, 1, "client", strconv.FormatUint(clientID, 10))
+
+	return &RateLimitError{
+		ClientID:   clientID,
+		Class:      class,
+		RetryAfter: retryAfter,
+	}
+}
+
+// Rejected returns the number of requests rejected for each client.
+func (rl *RateLimiter) Rejected() map[uint64]uint64 {
+	if rl == nil {
+		return map[uint64]uint64{}
+	}
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	result := make(map[uint64]uint64, len(rl.rejected))
+	for clientID, count := range rl.rejected {
+		result[clientID] = count
+	}
+	return result
+}
+
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take refills the bucket for the time elapsed, then attempts to consume n
+// tokens.  If too few are available, it returns the time until enough will be.
+func (tb *tokenBucket) take(now time.Time, n float64) (time.Duration, bool) {
+	if elapsed := now.Sub(tb.last); elapsed > 0 {
+		tb.tokens += elapsed.Seconds() * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+		tb.last = now
+	}
+
+	if tb.tokens >= n {
+		tb.tokens -= n
+		return 0, true
+	}
+
+	if tb.rate <= 0 || n > tb.burst {
+		return time.Duration(1<<63 - 1), false
+	}
+
+	return time.Duration((n - tb.tokens) / tb.rate * float64(time.Second)), false
+}
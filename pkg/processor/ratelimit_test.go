@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimiter", func() {
+	var (
+		now time.Time
+		rl  *RateLimiter
+	)
+
+	BeforeEach(func() {
+		now = time.Unix(0, 0)
+		rl = NewRateLimiter(&RateLimits{
+			Default: &RateLimit{Rate: 2, Burst: 2},
+			Clients: map[uint64]*RateLimit{
+				9: nil,
+			},
+			Classes: map[string]*RateLimit{
+				"shared": {Rate: 1, Burst: 1},
+			},
+			ClientClasses: map[uint64]string{
+				3: "shared",
+				4: "shared",
+			},
+		}, nil)
+		rl.now = func() time.Time { return now }
+	})
+
+	It("admits a burst, then refills at the rate", func() {
+		Expect(rl.Allow(1)).To(Succeed())
+		Expect(rl.Allow(1)).To(Succeed())
+
+		err := rl.Allow(1)
+		Expect(err).To(Equal(&RateLimitError{
+			ClientID:   1,
+			RetryAfter: 500 * time.Millisecond,
+		}))
+
+		now = now.Add(500 * time.Millisecond)
+		Expect(rl.Allow(1)).To(Succeed())
+		Expect(rl.Allow(1)).NotTo(Succeed())
+		Expect(rl.Rejected()).To(Equal(map[uint64]uint64{1: 2}))
+	})
+
+	It("limits each client separately", func() {
+		Expect(rl.AllowN(1, 2)).To(Succeed())
+		Expect(rl.AllowN(2, 2)).To(Succeed())
+	})
+
+	It("shares a bucket among the clients of a class", func() {
+		Expect(rl.Allow(3)).To(Succeed())
+		Expect(rl.Allow(4)).To(Equal(&RateLimitError{
+			ClientID:   4,
+			Class:      "shared",
+			RetryAfter: time.Second,
+		}))
+	})
+
+	It("does not limit clients with a nil limit", func() {
+		Expect(rl.AllowN(9, 100)).To(Succeed())
+	})
+
+	It("consumes either all or none of the tokens requested", func() {
+		Expect(rl.Allow(1)).To(Succeed())
+		Expect(rl.AllowN(1, 2)).To(Equal(&RateLimitError{
+			ClientID:   1,
+			RetryAfter: 500 * time.Millisecond,
+		}))
+		Expect(rl.Allow(1)).To(Succeed())
+	})
+
+	It("never admits more tokens than the burst", func() {
+		err := rl.AllowN(1, 3)
+		Expect(err).To(HaveOccurred())
+		Expect(err.(*RateLimitError).RetryAfter).To(Equal(time.Duration(1<<63 - 1)))
+		Expect(rl.AllowN(1, 2)).To(Succeed())
+	})
+
+	It("limits the forwards of each source separately from proposals", func() {
+		Expect(rl.AllowN(1, 2)).To(Succeed())
+		Expect(rl.AllowForward(0, 1)).To(Succeed())
+		Expect(rl.AllowForward(0, 1)).To(Succeed())
+		Expect(rl.AllowForward(0, 1)).NotTo(Succeed())
+		Expect(rl.AllowForward(1, 1)).To(Succeed())
+	})
+
+	It("admits everything when nil", func() {
+		var nilLimiter *RateLimiter
+		Expect(nilLimiter.AllowN(1, 100)).To(Succeed())
+		Expect(nilLimiter.AllowForward(0, 1)).To(Succeed())
+	})
+})
@@ -12,8 +12,9 @@ import (
 )
 
 type Replicas struct {
-	replicas map[uint64]*Replica
-	Clients  *Clients
+	replicas    map[uint64]*Replica
+	Clients     *Clients
+	RateLimiter *RateLimiter
 }
 
 func (rs *Replicas) Replica(id uint64) *Replica {
@@ -24,7 +25,8 @@ func (rs *Replicas) Replica(id uint64) *Replica {
 	r, ok := rs.replicas[id]
 	if !ok {
 		r = &Replica{
-			id: id,
+			id:          id,
+			rateLimiter: rs.RateLimiter,
 		}
 		rs.replicas[id] = r
 	}
@@ -32,7 +34,8 @@ func (rs *Replicas) Replica(id uint64) *Replica {
 }
 
 type Replica struct {
-	id uint64
+	id          uint64
+	rateLimiter *RateLimiter
 }
 
 func (r *Replica) Step(msg *msgs.Msg) (*statemachine.EventList, error) {
@@ -46,8 +49,12 @@ func (r *Replica) Step(msg *msgs.Msg) (*statemachine.EventList, error) {
 		// We handle messages of type Forward specially, as we don't
 		// want to pass them into the state machine, but instead buffer them
 		// externally.  This will also let us do manual validation for apps
-		// which attach signatures to their txes.
-		_ = t.ForwardRequest
+		// which attach signatures to their txes.  Forwarded requests beyond
+		// the client's rate limit are dropped, as the network would, which
+		// is safe as the replica may fetch the request again.
+		if r.rateLimiter.AllowForward(r.id, t.ForwardRequest.RequestAck.ClientId) != nil {
+			return &statemachine.EventList{}, nil
+		}
 		// TODO, implement
 		return &statemachine.EventList{}, nil
 	default:
@@ -0,0 +1,69 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package processor_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+)
+
+var _ = Describe("Replica", func() {
+	var replicas *processor.Replicas
+
+	BeforeEach(func() {
+		replicas = &processor.Replicas{
+			RateLimiter: processor.NewRateLimiter(&processor.RateLimits{
+				Default: &processor.RateLimit{Rate: 0, Burst: 1},
+			}, nil),
+		}
+	})
+
+	ack := func(reqNo uint64) *msgs.Msg {
+		return &msgs.Msg{
+			Type: &msgs.Msg_RequestAck{
+				RequestAck: &msgs.RequestAck{ClientId: 1, ReqNo: reqNo},
+			},
+		}
+	}
+
+	forward := func(reqNo uint64) *msgs.Msg {
+		return &msgs.Msg{
+			Type: &msgs.Msg_ForwardRequest{
+				ForwardRequest: &msgs.ForwardRequest{
+					RequestAck: &msgs.RequestAck{ClientId: 1, ReqNo: reqNo},
+				},
+			},
+		}
+	}
+
+	It("steps every ack, as the requests cannot become strong without them", func() {
+		for i := uint64(0); i < 3; i++ {
+			events, err := replicas.Replica(2).Step(ack(i))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(events.Len()).To(Equal(1))
+		}
+		Expect(replicas.RateLimiter.Rejected()).To(BeEmpty())
+	})
+
+	It("charges the forwarded requests of each replica to the client's rate limit", func() {
+		_, err := replicas.Replica(2).Step(forward(0))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas.RateLimiter.Rejected()).To(BeEmpty())
+
+		events, err := replicas.Replica(2).Step(forward(1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(events.Len()).To(Equal(0))
+		Expect(replicas.RateLimiter.Rejected()).To(Equal(map[uint64]uint64{1: 1}))
+
+		_, err = replicas.Replica(3).Step(forward(1))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replicas.RateLimiter.Rejected()).To(Equal(map[uint64]uint64{1: 1}))
+	})
+})
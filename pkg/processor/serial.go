@@ -33,6 +33,8 @@ type App interface {
 }
 
 type RequestStore interface {
+	// GetAllocation returns nil only for unallocated requests, the null
+	// request is allocated with a non-nil, empty digest.
 	GetAllocation(clientID, reqNo uint64) ([]byte, error)
 	PutAllocation(clientID, reqNo uint64, digest []byte) error
 	GetRequest(requestAck *msgs.RequestAck) ([]byte, error)
@@ -59,6 +61,53 @@ type EventInterceptor interface {
 	Intercept(s *state.Event) error
 }
 
+// ActionsInterceptor may optionally be implemented by an EventInterceptor
+// to additionally receive, with each ActionsReceived event, the actions the
+// state machine produced since the previous ActionsReceived event.
+type ActionsInterceptor interface {
+	// InterceptActions is invoked in place of Intercept for
+	// ActionsReceived events.
+	InterceptActions(event *state.Event, actions *statemachine.ActionList) error
+}
+
+// RequestDataInterceptor may optionally be implemented by an EventInterceptor
+// to additionally receive the data of the request persisted by each
+// RequestPersisted event.
+type RequestDataInterceptor interface {
+	// InterceptRequestData is invoked in place of Intercept for
+	// RequestPersisted events.  The data is only loaded from the
+	// request store if the interceptor invokes data.
+	InterceptRequestData(event *state.Event, data func() ([]byte, error)) error
+}
+
+// interceptEvent passes the event to the interceptor, along with the means to
+// load the persisted request if the interceptor is a RequestDataInterceptor.
+func interceptEvent(i EventInterceptor, reqStore RequestStore, event *state.Event) error {
+	rp, ok := event.Type.(*state.Event_RequestPersisted)
+	rdi, isRDI := i.(RequestDataInterceptor)
+	if !ok || !isRDI || reqStore == nil {
+		return i.Intercept(event)
+	}
+
+	return rdi.InterceptRequestData(event, func() ([]byte, error) {
+		ack := rp.RequestPersisted.RequestAck
+		if len(ack.Digest) == 0 {
+			// The null request has no data.
+			return nil, nil
+		}
+		return reqStore.GetRequest(ack)
+	})
+}
+
+// InterceptActionsReceived passes an ActionsReceived event to the interceptor,
+// along with the given actions if the interceptor is an ActionsInterceptor.
+func InterceptActionsReceived(i EventInterceptor, actions *statemachine.ActionList) error {
+	if ai, ok := i.(ActionsInterceptor); ok {
+		return ai.InterceptActions(statemachine.EventActionsReceived(), actions)
+	}
+	return i.Intercept(statemachine.EventActionsReceived())
+}
+
 func ProcessReqStoreEvents(reqStore RequestStore, events *statemachine.EventList) (*statemachine.EventList, error) {
 	// Then we sync the request store
 	if err := reqStore.Sync(); err != nil {
@@ -243,12 +292,15 @@ func safeApplyEvent(sm *statemachine.StateMachine, event *state.Event) (result *
 	return sm.ApplyEvent(event), nil
 }
 
-func ProcessStateMachineEvents(sm *statemachine.StateMachine, i EventInterceptor, events *statemachine.EventList) (*statemachine.ActionList, error) {
+// ProcessStateMachineEvents applies the events to the state machine, first
+// passing each to the interceptor, if any.  The request store, if non-nil,
+// supplies the data of persisted requests to a RequestDataInterceptor.
+func ProcessStateMachineEvents(sm *statemachine.StateMachine, i EventInterceptor, reqStore RequestStore, events *statemachine.EventList) (*statemachine.ActionList, error) {
 	actions := &statemachine.ActionList{}
 	iter := events.Iterator()
 	for event := iter.Next(); event != nil; event = iter.Next() {
 		if i != nil {
-			err := i.Intercept(event)
+			err := interceptEvent(i, reqStore, event)
 			if err != nil {
 				return nil, errors.WithMessage(err, "err intercepting event")
 			}
@@ -260,7 +312,7 @@ func ProcessStateMachineEvents(sm *statemachine.StateMachine, i EventInterceptor
 		actions.PushBackList(events)
 	}
 	if i != nil {
-		err := i.Intercept(statemachine.EventActionsReceived())
+		err := InterceptActionsReceived(i, actions)
 		if err != nil {
 			return nil, errors.WithMessage(err, "err intercepting close event")
 		}
@@ -111,6 +111,28 @@ func (pi *WorkItems) ResultEvents() *statemachine.EventList {
 	return pi.resultEvents
 }
 
+// Len returns the total number of actions and events outstanding.
+func (pi *WorkItems) Len() int {
+	return pi.WALActions().Len() +
+		pi.NetActions().Len() +
+		pi.HashActions().Len() +
+		pi.ClientActions().Len() +
+		pi.AppActions().Len() +
+		pi.ReqStoreEvents().Len() +
+		pi.ResultEvents().Len()
+}
+
+// AddWorkItems appends all outstanding work from other to these work items.
+func (pi *WorkItems) AddWorkItems(other *WorkItems) {
+	pi.WALActions().PushBackList(other.WALActions())
+	pi.NetActions().PushBackList(other.NetActions())
+	pi.HashActions().PushBackList(other.HashActions())
+	pi.ClientActions().PushBackList(other.ClientActions())
+	pi.AppActions().PushBackList(other.AppActions())
+	pi.ReqStoreEvents().PushBackList(other.ReqStoreEvents())
+	pi.ResultEvents().PushBackList(other.ResultEvents())
+}
+
 func (pi *WorkItems) AddHashResults(events *statemachine.EventList) {
 	pi.ResultEvents().PushBackList(events)
 }
@@ -62,6 +62,12 @@ func (s *Store) GetAllocation(clientID, reqNo uint64) ([]byte, error) {
 		}
 
 		valCopy, err = item.ValueCopy(nil)
+		if valCopy == nil {
+			// Badger returns nil for empty values, but the null request
+			// is allocated with an empty digest, which must remain
+			// distinguishable from an unallocated request.
+			valCopy = []byte{}
+		}
 		return err
 	})
 
@@ -125,16 +125,18 @@ func ActionPersist(index uint64, p *msgs.Persistent) *state.Action {
 	}
 }
 
-func (al *ActionList) Commit(qEntry *msgs.QEntry) *ActionList {
-	al.PushBack(ActionCommit(qEntry))
+func (al *ActionList) Commit(qEntry *msgs.QEntry, epoch, checkpointSeqNo uint64) *ActionList {
+	al.PushBack(ActionCommit(qEntry, epoch, checkpointSeqNo))
 	return al
 }
 
-func ActionCommit(qEntry *msgs.QEntry) *state.Action {
+func ActionCommit(qEntry *msgs.QEntry, epoch, checkpointSeqNo uint64) *state.Action {
 	return &state.Action{
 		Type: &state.Action_Commit{
 			Commit: &state.ActionCommit{
-				Batch: qEntry,
+				Batch:           qEntry,
+				Epoch:           epoch,
+				CheckpointSeqNo: checkpointSeqNo,
 			},
 		},
 	}
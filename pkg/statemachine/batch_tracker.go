@@ -9,9 +9,11 @@ package statemachine
 import (
 	"bytes"
 	"fmt"
+	"sort"
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
 )
 
 type batchTracker struct {
@@ -225,3 +227,17 @@ func (bt *batchTracker) getBatch(digest []byte) (*batch, bool) {
 	b, ok := bt.batchesByDigest[string(digest)]
 	return b, ok
 }
+
+func (bt *batchTracker) status() []*status.BatchFetch {
+	result := make([]*status.BatchFetch, 0, len(bt.fetchInFlight))
+	for digest, seqNos := range bt.fetchInFlight {
+		result = append(result, &status.BatchFetch{
+			Digest: []byte(digest),
+			SeqNos: append([]uint64{}, seqNos...),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].Digest, result[j].Digest) < 0
+	})
+	return result
+}
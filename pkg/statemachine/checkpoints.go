@@ -47,7 +47,7 @@ func newCheckpointTracker(seqNo uint64, networkState *msgs.NetworkState, persist
 		state:       cpsIdle,
 		persisted:   persisted,
 		nodeBuffers: nodeBuffers,
-		logger:      logger,
+		logger:      componentLogger(logger, "checkpoints"),
 	}
 
 	return ct
@@ -82,11 +82,15 @@ func (ct *checkpointTracker) reinitialize() {
 	for _, id := range ct.networkConfig.Nodes {
 		if buffer, ok := oldMsgBuffers[nodeID(id)]; ok {
 			ct.msgBuffers[nodeID(id)] = buffer
+			delete(oldMsgBuffers, nodeID(id))
 		} else {
 			ct.msgBuffers[nodeID(id)] = newMsgBuffer("checkpoints", ct.nodeBuffers.nodeBuffer(nodeID(id)))
 		}
 		validNodes[nodeID(id)] = struct{}{}
 	}
+	for _, buffer := range oldMsgBuffers {
+		buffer.discard()
+	}
 
 	// Lots of non-determinism in this iteration... but it should
 	// all be commutative.
@@ -240,6 +244,17 @@ func (ct *checkpointTracker) applyCheckpointMsg(source nodeID, seqNo uint64, val
 	}
 }
 
+// highestStable returns the sequence number of the highest stable checkpoint.
+func (ct *checkpointTracker) highestStable() uint64 {
+	var result uint64
+	for seqNo, cp := range ct.checkpointMap {
+		if cp.stable && seqNo > result {
+			result = seqNo
+		}
+	}
+	return result
+}
+
 func (ct *checkpointTracker) status() []*status.Checkpoint {
 	result := make([]*status.Checkpoint, len(ct.checkpointMap))
 	i := 0
@@ -316,5 +331,6 @@ func (cw *checkpoint) status() *status.Checkpoint {
 		MaxAgreements: maxAgreements,
 		NetQuorum:     cw.committedValue != nil,
 		LocalDecision: cw.myValue != nil,
+		Value:         cw.myValue,
 	}
 }
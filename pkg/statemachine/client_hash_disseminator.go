@@ -120,6 +120,7 @@ import (
 
 type clientHashDisseminator struct {
 	logger      Logger
+	tracer      RequestTracer
 	myConfig    *state.EventInitialParameters
 	nodeBuffers *nodeBuffers
 
@@ -131,9 +132,10 @@ type clientHashDisseminator struct {
 	clientTracker    *clientTracker
 }
 
-func newClientHashDisseminator(nodeBuffers *nodeBuffers, myConfig *state.EventInitialParameters, logger Logger, clientTracker *clientTracker) *clientHashDisseminator {
+func newClientHashDisseminator(nodeBuffers *nodeBuffers, myConfig *state.EventInitialParameters, logger Logger, tracer RequestTracer, clientTracker *clientTracker) *clientHashDisseminator {
 	return &clientHashDisseminator{
-		logger:        logger,
+		logger:        componentLogger(logger, "client_hash_disseminator"),
+		tracer:        tracer,
 		myConfig:      myConfig,
 		nodeBuffers:   nodeBuffers,
 		clientTracker: clientTracker,
@@ -158,7 +160,7 @@ func (ct *clientHashDisseminator) reinitialize(seqNo uint64, networkState *msgs.
 	for _, clientState := range ct.clientStates {
 		client, ok := oldClients[clientState.Id]
 		if !ok {
-			client = newClient(ct.myConfig, ct.logger, ct.clientTracker)
+			client = newClient(ct.myConfig, ct.logger, ct.tracer, ct.clientTracker)
 		}
 
 		ct.clients[clientState.Id] = client
@@ -171,10 +173,14 @@ func (ct *clientHashDisseminator) reinitialize(seqNo uint64, networkState *msgs.
 	for _, id := range networkState.Config.Nodes {
 		if oldBuffer, ok := oldMsgBuffers[nodeID(id)]; ok {
 			ct.msgBuffers[nodeID(id)] = oldBuffer
+			delete(oldMsgBuffers, nodeID(id))
 		} else {
 			ct.msgBuffers[nodeID(id)] = newMsgBuffer("clients", ct.nodeBuffers.nodeBuffer(nodeID(id)))
 		}
 	}
+	for _, oldBuffer := range oldMsgBuffers {
+		oldBuffer.discard()
+	}
 
 	return actions
 }
@@ -628,6 +634,28 @@ func (crn *clientReqNo) tick() *ActionList {
 	return actions
 }
 
+func (crn *clientReqNo) status() *status.ReqNo {
+	requests := make([]*status.Request, 0, len(crn.requests))
+	for _, cr := range crn.requests {
+		requests = append(requests, &status.Request{
+			Digest:        cr.ack.Digest,
+			Acks:          len(cr.agreements),
+			Stored:        cr.stored,
+			Fetching:      cr.fetching,
+			TicksFetching: cr.ticksFetching,
+		})
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return bytes.Compare(requests[i].Digest, requests[j].Digest) < 0
+	})
+
+	return &status.ReqNo{
+		ReqNo:    crn.reqNo,
+		AcksSent: crn.acksSent,
+		Requests: requests,
+	}
+}
+
 type clientRequest struct {
 	myConfig      *state.EventInitialParameters
 	ack           *msgs.RequestAck
@@ -670,6 +698,7 @@ func (cr *clientRequest) fetch() *ActionList {
 type client struct {
 	myConfig      *state.EventInitialParameters
 	logger        Logger
+	tracer        RequestTracer
 	networkConfig *msgs.NetworkState_Config
 	clientState   *msgs.NetworkState_Client
 	clientTracker *clientTracker
@@ -681,10 +710,11 @@ type client struct {
 	reqNoMap  map[uint64]*list.Element
 }
 
-func newClient(myConfig *state.EventInitialParameters, logger Logger, tracker *clientTracker) *client {
+func newClient(myConfig *state.EventInitialParameters, logger Logger, tracer RequestTracer, tracker *clientTracker) *client {
 	return &client{
 		myConfig:      myConfig,
-		logger:        logger,
+		logger:        componentLogger(logger, "client_hash_disseminator"),
+		tracer:        tracer,
 		clientTracker: tracker,
 	}
 }
@@ -817,6 +847,10 @@ func (c *client) ack(source nodeID, ack *msgs.RequestAck) (*ActionList, *clientR
 	if newlyCorrect {
 		crn.weakRequests[string(ack.Digest)] = cr
 
+		if c.tracer != nil {
+			c.tracer.RequestCorrect(ack)
+		}
+
 		if !cr.stored {
 			// If we already have the req stored, we know it's correct
 			actions.CorrectRequest(ack)
@@ -832,6 +866,10 @@ func (c *client) ack(source nodeID, ack *msgs.RequestAck) (*ActionList, *clientR
 	if len(cr.agreements) == intersectionQuorum(c.networkConfig) {
 		crn.strongRequests[string(ack.Digest)] = cr
 
+		if c.tracer != nil {
+			c.tracer.RequestStrong(ack)
+		}
+
 		// Check to see if this request just becoming 'ready' can advance the ready mark
 		c.advanceReady()
 	}
@@ -919,10 +957,20 @@ func (c *client) status() *status.ClientTracker {
 		i++
 	}
 
+	reqNos := []*status.ReqNo{}
+	for el := c.reqNoList.Front(); el != nil; el = el.Next() {
+		crn := el.Value.(*clientReqNo)
+		if crn.committed || len(crn.requests) == 0 {
+			continue
+		}
+		reqNos = append(reqNos, crn.status())
+	}
+
 	return &status.ClientTracker{
 		ClientID:      c.clientState.Id,
 		LowWatermark:  c.clientState.LowWatermark,
 		HighWatermark: c.highWatermark,
 		Allocated:     allocated[:lastNonZero],
+		ReqNos:        reqNos,
 	}
 }
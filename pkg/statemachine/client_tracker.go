@@ -25,7 +25,7 @@ type clientTracker struct {
 
 func newClientTracker(myConfig *state.EventInitialParameters, logger Logger) *clientTracker {
 	return &clientTracker{
-		logger:   logger,
+		logger:   componentLogger(logger, "client_tracker"),
 		myConfig: myConfig,
 	}
 }
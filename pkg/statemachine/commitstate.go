@@ -11,6 +11,7 @@ import (
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
 )
 
 // commitState represents our state, as reflected within our log watermarks.
@@ -31,8 +32,8 @@ type commitState struct {
 	highestCommit     uint64 // Highest in order commit sequence number. All SNs up to highestCommit are committed.
 	stopAtSeqNo       uint64
 	activeState       *msgs.NetworkState
-	lowerHalfCommits  []*msgs.QEntry
-	upperHalfCommits  []*msgs.QEntry
+	lowerHalfCommits  []*state.ActionCommit
+	upperHalfCommits  []*state.ActionCommit
 	checkpointPending bool
 	transferring      bool
 }
@@ -40,7 +41,7 @@ type commitState struct {
 func newCommitState(persisted *persisted, logger Logger) *commitState {
 	cs := &commitState{
 		persisted: persisted,
-		logger:    logger,
+		logger:    componentLogger(logger, "commitstate"),
 	}
 
 	return cs
@@ -80,8 +81,8 @@ func (cs *commitState) reinitialize() *ActionList {
 	cs.lastAppliedCommit = lastCEntry.SeqNo
 	cs.highestCommit = lastCEntry.SeqNo
 
-	cs.lowerHalfCommits = make([]*msgs.QEntry, ci)
-	cs.upperHalfCommits = make([]*msgs.QEntry, ci)
+	cs.lowerHalfCommits = make([]*state.ActionCommit, ci)
+	cs.upperHalfCommits = make([]*state.ActionCommit, ci)
 
 	cs.committingClients = map[uint64]*committingClient{}
 	for _, clientState := range lastCEntry.NetworkState.Clients {
@@ -131,7 +132,7 @@ func (cs *commitState) applyCheckpointResult(epochConfig *msgs.EpochConfig, resu
 
 	cs.activeState = result.NetworkState
 	cs.lowerHalfCommits = cs.upperHalfCommits
-	cs.upperHalfCommits = make([]*msgs.QEntry, ci)
+	cs.upperHalfCommits = make([]*state.ActionCommit, ci)
 	cs.lowWatermark = result.SeqNo
 	cs.checkpointPending = false
 
@@ -152,7 +153,7 @@ func (cs *commitState) applyCheckpointResult(epochConfig *msgs.EpochConfig, resu
 	).StateApplied(result.SeqNo, result.NetworkState)
 }
 
-func (cs *commitState) commit(qEntry *msgs.QEntry) {
+func (cs *commitState) commit(epoch uint64, qEntry *msgs.QEntry) {
 	assertEqual(cs.transferring, false, "we should never commit during state transfer")
 	assertGreaterThanOrEqual(cs.stopAtSeqNo, qEntry.SeqNo, "commit sequence exceeds stop sequence")
 
@@ -171,7 +172,7 @@ func (cs *commitState) commit(qEntry *msgs.QEntry) {
 	ci := uint64(cs.activeState.Config.CheckpointInterval)
 	upper := qEntry.SeqNo-cs.lowWatermark > ci
 	offset := int((qEntry.SeqNo - (cs.lowWatermark + 1)) % ci)
-	var commits []*msgs.QEntry
+	var commits []*state.ActionCommit
 	if upper {
 		commits = cs.upperHalfCommits
 	} else {
@@ -179,9 +180,18 @@ func (cs *commitState) commit(qEntry *msgs.QEntry) {
 	}
 
 	if commits[offset] != nil {
-		assertTruef(bytes.Equal(commits[offset].Digest, qEntry.Digest), "previously committed %x but now have %x for seq_no=%d", commits[offset].Digest, qEntry.Digest, qEntry.SeqNo)
+		assertTruef(bytes.Equal(commits[offset].Batch.Digest, qEntry.Digest), "previously committed %x but now have %x for seq_no=%d", commits[offset].Batch.Digest, qEntry.Digest, qEntry.SeqNo)
 	} else {
-		commits[offset] = qEntry
+		checkpointSeqNo := cs.lowWatermark + ci
+		if upper {
+			checkpointSeqNo += ci
+		}
+
+		commits[offset] = &state.ActionCommit{
+			Batch:           qEntry,
+			Epoch:           epoch,
+			CheckpointSeqNo: checkpointSeqNo,
+		}
 	}
 }
 
@@ -243,7 +253,7 @@ func (cs *commitState) drain() *ActionList {
 		nextCommit := cs.lastAppliedCommit + 1
 		upper := nextCommit-cs.lowWatermark > ci
 		offset := int((nextCommit - (cs.lowWatermark + 1)) % ci)
-		var commits []*msgs.QEntry
+		var commits []*state.ActionCommit
 		if upper {
 			commits = cs.upperHalfCommits
 		} else {
@@ -254,12 +264,12 @@ func (cs *commitState) drain() *ActionList {
 			break
 		}
 
-		assertEqual(commit.SeqNo, nextCommit, "attempted out of order commit")
+		assertEqual(commit.Batch.SeqNo, nextCommit, "attempted out of order commit")
 
-		actions.Commit(commit)
+		actions.Commit(commit.Batch, commit.Epoch, commit.CheckpointSeqNo)
 
-		for _, req := range commit.Requests {
-			cs.committingClients[req.ClientId].markCommitted(commit.SeqNo, req.ReqNo)
+		for _, req := range commit.Batch.Requests {
+			cs.committingClients[req.ClientId].markCommitted(commit.Batch.SeqNo, req.ReqNo)
 		}
 
 		cs.lastAppliedCommit = nextCommit
@@ -364,3 +374,44 @@ func (cc *committingClient) createCheckpointState() (newState *msgs.NetworkState
 		CommittedMask:               mask,
 	}
 }
+
+func (cs *commitState) status() *status.CommitState {
+	result := &status.CommitState{
+		LowWatermark:            cs.lowWatermark,
+		LastAppliedCommit:       cs.lastAppliedCommit,
+		HighestCommit:           cs.highestCommit,
+		StopAtSeqNo:             cs.stopAtSeqNo,
+		CheckpointPending:       cs.checkpointPending,
+		Transferring:            cs.transferring,
+		PendingReconfigurations: []*status.Reconfiguration{},
+	}
+
+	if cs.activeState == nil {
+		return result
+	}
+
+	for _, reconfig := range cs.activeState.PendingReconfigurations {
+		switch rc := reconfig.Type.(type) {
+		case *msgs.Reconfiguration_NewClient_:
+			result.PendingReconfigurations = append(result.PendingReconfigurations, &status.Reconfiguration{
+				Type:     "new_client",
+				ClientID: rc.NewClient.Id,
+				Width:    rc.NewClient.Width,
+			})
+		case *msgs.Reconfiguration_RemoveClient:
+			result.PendingReconfigurations = append(result.PendingReconfigurations, &status.Reconfiguration{
+				Type:     "remove_client",
+				ClientID: rc.RemoveClient,
+			})
+		case *msgs.Reconfiguration_NewConfig:
+			result.PendingReconfigurations = append(result.PendingReconfigurations, &status.Reconfiguration{
+				Type:            "new_config",
+				Nodes:           rc.NewConfig.Nodes,
+				F:               rc.NewConfig.F,
+				NumberOfBuckets: rc.NewConfig.NumberOfBuckets,
+			})
+		}
+	}
+
+	return result
+}
@@ -43,6 +43,7 @@ type activeEpoch struct {
 }
 
 func newActiveEpoch(epochConfig *msgs.EpochConfig, persisted *persisted, nodeBuffers *nodeBuffers, commitState *commitState, clientTracker *clientTracker, myConfig *state.EventInitialParameters, logger Logger) *activeEpoch {
+	logger = componentLogger(logger, "epoch_active")
 	networkConfig := commitState.activeState.Config
 	startingSeqNo := commitState.highestCommit
 
@@ -121,6 +122,16 @@ func newActiveEpoch(epochConfig *msgs.EpochConfig, persisted *persisted, nodeBuf
 	}
 }
 
+func (e *activeEpoch) discardBuffers() {
+	for _, ppBuffer := range e.preprepareBuffers {
+		ppBuffer.buffer.discard()
+	}
+
+	for _, buffer := range e.otherBuffers {
+		buffer.discard()
+	}
+}
+
 func (e *activeEpoch) seqToBucket(seqNo uint64) bucketID {
 	return seqToBucket(seqNo, e.networkConfig)
 }
@@ -309,7 +320,7 @@ func (e *activeEpoch) applyCommitMsg(source nodeID, seqNo uint64, digest []byte)
 			break
 		}
 
-		e.commitState.commit(seq.qEntry)
+		e.commitState.commit(e.epochConfig.Number, seq.qEntry)
 		e.lowestUncommitted++
 	}
 
@@ -105,7 +105,17 @@ func newEpochTarget(
 		batchTracker:           batchTracker,
 		networkConfig:          networkConfig,
 		myConfig:               myConfig,
-		logger:                 logger,
+		logger:                 componentLogger(logger, "epoch_target"),
+	}
+}
+
+func (et *epochTarget) discardBuffers() {
+	for _, buffer := range et.prestartBuffers {
+		buffer.discard()
+	}
+
+	if et.activeEpoch != nil {
+		et.activeEpoch.discardBuffers()
 	}
 }
 
@@ -759,7 +769,7 @@ func (et *epochTarget) checkNewEpochReadyQuorum() {
 				}
 
 				et.logger.Log(LevelDebug, "epoch change triggering commit", "epoch_no", et.number, "seq_no", qEntry.SeqNo)
-				et.commitState.commit(qEntry)
+				et.commitState.commit(et.number, qEntry)
 			},
 			onECEntry: func(ecEntry *msgs.ECEntry) {
 				if ecEntry.EpochNumber < config.Config.Number {
@@ -873,12 +883,9 @@ func (et *epochTarget) applySuspectMsg(source nodeID) {
 	}
 }
 
-func (et *epochTarget) bucketStatus() (lowWatermark, highWatermark uint64, bucketStatus []*status.Bucket) {
+func (et *epochTarget) watermarks() (lowWatermark, highWatermark uint64) {
 	if et.activeEpoch != nil && len(et.activeEpoch.sequences) != 0 {
-		bucketStatus = et.activeEpoch.status()
-		lowWatermark = et.activeEpoch.lowWatermark()
-		highWatermark = et.activeEpoch.highWatermark()
-		return
+		return et.activeEpoch.lowWatermark(), et.activeEpoch.highWatermark()
 	}
 
 	if et.state <= etFetching || et.leaderNewEpoch == nil {
@@ -891,6 +898,17 @@ func (et *epochTarget) bucketStatus() (lowWatermark, highWatermark uint64, bucke
 		highWatermark = lowWatermark + uint64(2*et.networkConfig.CheckpointInterval) - 1
 	}
 
+	return lowWatermark, highWatermark
+}
+
+func (et *epochTarget) bucketStatus() (lowWatermark, highWatermark uint64, bucketStatus []*status.Bucket) {
+	lowWatermark, highWatermark = et.watermarks()
+
+	if et.activeEpoch != nil && len(et.activeEpoch.sequences) != 0 {
+		bucketStatus = et.activeEpoch.status()
+		return
+	}
+
 	bucketStatus = make([]*status.Bucket, int(et.networkConfig.NumberOfBuckets))
 	for i := range bucketStatus {
 		bucketStatus[i] = &status.Bucket{
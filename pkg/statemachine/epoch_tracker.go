@@ -49,7 +49,7 @@ func newEpochTracker(
 		nodeBuffers:            nodeBuffers,
 		commitState:            commitState,
 		myConfig:               myConfig,
-		logger:                 logger,
+		logger:                 componentLogger(logger, "epoch_tracker"),
 		batchTracker:           batchTracker,
 		clientTracker:          clientTracker,
 		clientHashDisseminator: clientHashDisseminator,
@@ -57,6 +57,15 @@ func newEpochTracker(
 	}
 }
 
+// setCurrentEpoch replaces the current epoch target, discarding the
+// messages buffered by the one it replaces.
+func (et *epochTracker) setCurrentEpoch(target *epochTarget) {
+	if et.currentEpoch != nil {
+		et.currentEpoch.discardBuffers()
+	}
+	et.currentEpoch = target
+}
+
 func (et *epochTracker) reinitialize() *ActionList {
 	et.networkConfig = et.commitState.activeState.Config
 
@@ -70,6 +79,10 @@ func (et *epochTracker) reinitialize() *ActionList {
 			)
 		}
 		newFutureMsgs[nodeID(id)] = futureMsgs
+		delete(et.futureMsgs, nodeID(id))
+	}
+	for _, futureMsgs := range et.futureMsgs {
+		futureMsgs.discard()
 	}
 	et.futureMsgs = newFutureMsgs
 
@@ -127,7 +140,7 @@ func (et *epochTracker) reinitialize() *ActionList {
 	case lastNEntry != nil && (lastECEntry == nil || lastECEntry.EpochNumber <= lastNEntry.EpochConfig.Number):
 		et.logger.Log(LevelDebug, "reinitializing during a currently active epoch")
 
-		et.currentEpoch = newEpochTarget(
+		et.setCurrentEpoch(newEpochTarget(
 			lastNEntry.EpochConfig.Number,
 			et.persisted,
 			et.nodeBuffers,
@@ -138,7 +151,7 @@ func (et *epochTracker) reinitialize() *ActionList {
 			et.networkConfig,
 			et.myConfig,
 			et.logger,
-		)
+		))
 
 		startingSeqNo := highestPreprepared + 1
 		for startingSeqNo%uint64(et.networkConfig.CheckpointInterval) != 1 {
@@ -184,7 +197,7 @@ func (et *epochTracker) reinitialize() *ActionList {
 		parsedEpochChange, err := newParsedEpochChange(epochChange)
 		assertEqualf(err, nil, "could not parse epoch change we generated: %s", err)
 
-		et.currentEpoch = newEpochTarget(
+		et.setCurrentEpoch(newEpochTarget(
 			epochChange.NewEpoch,
 			et.persisted,
 			et.nodeBuffers,
@@ -195,7 +208,7 @@ func (et *epochTracker) reinitialize() *ActionList {
 			et.networkConfig,
 			et.myConfig,
 			et.logger,
-		)
+		))
 
 		et.currentEpoch.myEpochChange = parsedEpochChange
 
@@ -237,7 +250,7 @@ func (et *epochTracker) advanceState() *ActionList {
 	myEpochChange, err := newParsedEpochChange(epochChange)
 	assertEqualf(err, nil, "could not parse epoch change we generated: %s", err)
 
-	et.currentEpoch = newEpochTarget(
+	et.setCurrentEpoch(newEpochTarget(
 		newEpochNumber,
 		et.persisted,
 		et.nodeBuffers,
@@ -248,7 +261,7 @@ func (et *epochTracker) advanceState() *ActionList {
 		et.networkConfig,
 		et.myConfig,
 		et.logger,
-	)
+	))
 	et.currentEpoch.myEpochChange = myEpochChange
 	et.currentEpoch.myLeaderChoice = []uint64{et.myConfig.Id} // XXX, wrong
 
@@ -207,6 +207,19 @@ func EventTickElapsed() *state.Event {
 	}
 }
 
+func (el *EventList) UpdateParameters(parameters *state.EventUpdateParameters) *EventList {
+	el.PushBack(EventUpdateParameters(parameters))
+	return el
+}
+
+func EventUpdateParameters(parameters *state.EventUpdateParameters) *state.Event {
+	return &state.Event{
+		Type: &state.Event_UpdateParameters{
+			UpdateParameters: parameters,
+		},
+	}
+}
+
 func (el *EventList) ActionsReceived() *EventList {
 	el.PushBack(EventActionsReceived())
 	return el
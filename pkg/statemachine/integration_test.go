@@ -119,6 +119,8 @@ var _ = Describe("Mirbft", func() {
 
 			status, err := node.StateMachine.Status()
 			Expect(err).NotTo(HaveOccurred())
+			Expect(status.CommitState.LastAppliedCommit).To(BeNumerically(">=", status.CommitState.LowWatermark))
+			Expect(status.Pretty()).To(ContainSubstring("Commit State"))
 			isLeader := false
 			for _, leader := range status.EpochTracker.ActiveEpoch.Leaders {
 				if leader == nodeID {
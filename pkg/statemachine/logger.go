@@ -65,3 +65,26 @@ type Logger interface {
 	// values are unspecified.
 	Log(level LogLevel, text string, args ...interface{})
 }
+
+// componentLogger returns a logger which prefixes the key/value pairs of each
+// log message with "component" and the name of the logging component, for
+// instance "epoch_target", so that log output may be filtered by component.
+func componentLogger(logger Logger, component string) Logger {
+	if tl, ok := logger.(taggedLogger); ok {
+		logger = tl.logger
+	}
+
+	return taggedLogger{
+		logger:    logger,
+		component: component,
+	}
+}
+
+type taggedLogger struct {
+	logger    Logger
+	component string
+}
+
+func (tl taggedLogger) Log(level LogLevel, text string, args ...interface{}) {
+	tl.logger.Log(level, text, append([]interface{}{"component", tl.component}, args...)...)
+}
@@ -20,30 +20,55 @@ type nodeBuffers struct {
 	logger   Logger
 	myConfig *state.EventInitialParameters
 	nodeMap  map[nodeID]*nodeBuffer
+
+	// msgBufs is the set of non-empty msgBuffers of every component for
+	// each node.  Used for metrics only.
+	msgBufs map[nodeID]map[*msgBuffer]struct{}
 }
 
 func newNodeBuffers(myConfig *state.EventInitialParameters, logger Logger) *nodeBuffers {
 	return &nodeBuffers{
-		logger:   logger,
+		logger:   componentLogger(logger, "msgbuffers"),
 		myConfig: myConfig,
 		nodeMap:  map[nodeID]*nodeBuffer{},
+		msgBufs:  map[nodeID]map[*msgBuffer]struct{}{},
 	}
 }
 
 func (nbs *nodeBuffers) nodeBuffer(source nodeID) *nodeBuffer {
 	nb, ok := nbs.nodeMap[source]
 	if !ok {
+		nodeMsgBufs, ok := nbs.msgBufs[source]
+		if !ok {
+			nodeMsgBufs = map[*msgBuffer]struct{}{}
+			nbs.msgBufs[source] = nodeMsgBufs
+		}
+
 		nb = &nodeBuffer{
-			id:       source,
-			logger:   nbs.logger,
-			myConfig: nbs.myConfig,
-			msgBufs:  map[*msgBuffer]struct{}{},
+			id:          source,
+			logger:      nbs.logger,
+			myConfig:    nbs.myConfig,
+			msgBufs:     map[*msgBuffer]struct{}{},
+			nodeMsgBufs: nodeMsgBufs,
 		}
 	}
 
 	return nb
 }
 
+// sizes returns the total bytes of messages buffered for each node.
+func (nbs *nodeBuffers) sizes() map[uint64]int {
+	result := make(map[uint64]int, len(nbs.msgBufs))
+	for id, msgBufs := range nbs.msgBufs {
+		size := 0
+		for mb := range msgBufs {
+			size += mb.size
+		}
+		result[uint64(id)] = size
+	}
+	return result
+}
+
 func (nbs *nodeBuffers) status() []*status.NodeBuffer {
 	// Create status objects.
 	stats := make([]*status.NodeBuffer, 0, len(nbs.nodeMap))
@@ -68,10 +93,14 @@ type nodeBuffer struct {
 	// Set of pointers to msgBuffers tracked by this nodeBuffer.
 	// Used for logging and status only.
 	msgBufs map[*msgBuffer]struct{}
+
+	// nodeMsgBufs is shared by the nodeBuffers of every component
+	// for this node.  Used for metrics only.
+	nodeMsgBufs map[*msgBuffer]struct{}
 }
 
 func (nb *nodeBuffer) logDrop(component string, msg *msgs.Msg) {
-	nb.logger.Log(LevelWarn, "dropping buffered msg", "component", component, "type", fmt.Sprintf("%T", msg.Type))
+	nb.logger.Log(LevelWarn, "dropping buffered msg", "buffer", component, "type", fmt.Sprintf("%T", msg.Type))
 }
 
 func (nb *nodeBuffer) msgRemoved(msg *msgs.Msg) {
@@ -88,10 +117,12 @@ func (nb *nodeBuffer) overCapacity() bool {
 
 func (nb *nodeBuffer) addMsgBuffer(msgBuf *msgBuffer) {
 	nb.msgBufs[msgBuf] = struct{}{}
+	nb.nodeMsgBufs[msgBuf] = struct{}{}
 }
 
 func (nb *nodeBuffer) removeMsgBuffer(msgBuf *msgBuffer) {
 	delete(nb.msgBufs, msgBuf)
+	delete(nb.nodeMsgBufs, msgBuf)
 }
 
 func (nb *nodeBuffer) status() *status.NodeBuffer {
@@ -132,6 +163,7 @@ type msgBuffer struct {
 	component  string
 	buffer     *list.List
 	nodeBuffer *nodeBuffer
+	size       int
 }
 
 func newMsgBuffer(component string, nodeBuffer *nodeBuffer) *msgBuffer {
@@ -154,6 +186,7 @@ func (mb *msgBuffer) store(msg *msgs.Msg) {
 		mb.nodeBuffer.logDrop(mb.component, oldMsg)
 	}
 	mb.buffer.PushBack(msg)
+	mb.size += proto.Size(msg)
 	mb.nodeBuffer.msgStored(msg)
 	if mb.buffer.Len() == 1 {
 		// If this is the first message in this msgBuffer,
@@ -165,6 +198,7 @@ func (mb *msgBuffer) store(msg *msgs.Msg) {
 
 func (mb *msgBuffer) remove(e *list.Element) *msgs.Msg {
 	msg := mb.buffer.Remove(e).(*msgs.Msg)
+	mb.size -= proto.Size(msg)
 	mb.nodeBuffer.msgRemoved(msg)
 	if mb.buffer.Len() == 0 {
 		// If the last message was removed,
@@ -175,6 +209,14 @@ func (mb *msgBuffer) remove(e *list.Element) *msgs.Msg {
 	return msg
 }
 
+// discard removes every message from a msgBuffer which is being replaced,
+// so that they no longer count as buffered.
+func (mb *msgBuffer) discard() {
+	for mb.buffer.Len() > 0 {
+		mb.remove(mb.buffer.Front())
+	}
+}
+
 func (mb *msgBuffer) next(filter func(source nodeID, msg *msgs.Msg) applyable) *msgs.Msg {
 	e := mb.buffer.Front()
 	if e == nil {
@@ -226,14 +268,9 @@ func (mb *msgBuffer) iterate(
 }
 
 func (mb *msgBuffer) status() *status.MsgBuffer {
-	totalSize := 0
-	for e := mb.buffer.Front(); e != nil; e = e.Next() {
-		totalSize += proto.Size(e.Value.(*msgs.Msg))
-	}
-
 	return &status.MsgBuffer{
 		Component: mb.component,
-		Size:      totalSize,
+		Size:      mb.size,
 		Msgs:      mb.buffer.Len(),
 	}
 }
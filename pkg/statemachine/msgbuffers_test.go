@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemachine
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+)
+
+var _ = Describe("nodeBuffers", func() {
+	var (
+		nbs *nodeBuffers
+		msg *msgs.Msg
+	)
+
+	BeforeEach(func() {
+		msg = &msgs.Msg{
+			Type: &msgs.Msg_Suspect{
+				Suspect: &msgs.Suspect{Epoch: 1},
+			},
+		}
+
+		// Buffers are over capacity once they exceed the buffer size, so
+		// this admits only a single message.
+		nbs = newNodeBuffers(&state.EventInitialParameters{
+			BufferSize: uint32(proto.Size(msg) - 1),
+		}, ConsoleErrorLogger)
+	})
+
+	It("limits each component's buffer separately while reporting the total per node", func() {
+		mb1 := newMsgBuffer("component1", nbs.nodeBuffer(1))
+		mb2 := newMsgBuffer("component2", nbs.nodeBuffer(1))
+
+		mb1.store(msg)
+		mb2.store(msg)
+		Expect(mb1.buffer.Len()).To(Equal(1))
+		Expect(mb2.buffer.Len()).To(Equal(1))
+		Expect(nbs.sizes()).To(Equal(map[uint64]int{1: 2 * proto.Size(msg)}))
+
+		mb1.store(msg)
+		Expect(mb1.buffer.Len()).To(Equal(1))
+		Expect(nbs.sizes()).To(Equal(map[uint64]int{1: 2 * proto.Size(msg)}))
+
+		Expect(mb2.next(func(nodeID, *msgs.Msg) applyable { return current })).To(Equal(msg))
+		Expect(nbs.sizes()).To(Equal(map[uint64]int{1: proto.Size(msg)}))
+	})
+
+	It("stops reporting the messages buffered by a replaced epoch", func() {
+		networkConfig := &msgs.NetworkState_Config{Nodes: []uint64{0, 1}}
+		newTarget := func(number uint64) *epochTarget {
+			return newEpochTarget(number, nil, nbs, nil, nil, nil, nil, networkConfig, &state.EventInitialParameters{}, ConsoleErrorLogger)
+		}
+
+		et := &epochTracker{}
+		et.setCurrentEpoch(newTarget(1))
+		et.currentEpoch.step(1, msg)
+		Expect(nbs.sizes()).To(Equal(map[uint64]int{0: 0, 1: proto.Size(msg)}))
+
+		et.setCurrentEpoch(newTarget(2))
+		Expect(nbs.sizes()).To(Equal(map[uint64]int{0: 0, 1: 0}))
+	})
+})
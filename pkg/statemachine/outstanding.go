@@ -27,6 +27,7 @@ func ackToKey(ack *msgs.RequestAck) ackKey {
 }
 
 func newOutstandingReqs(clientTracker *clientTracker, networkState *msgs.NetworkState, logger Logger) *allOutstandingReqs {
+	logger = componentLogger(logger, "outstanding")
 	clientTracker.availableList.resetIterator()
 
 	ao := &allOutstandingReqs{
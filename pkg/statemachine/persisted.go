@@ -41,7 +41,7 @@ type persisted struct {
 
 func newPersisted(logger Logger) *persisted {
 	return &persisted{
-		logger: logger,
+		logger: componentLogger(logger, "persisted"),
 	}
 }
 
@@ -27,7 +27,9 @@ type proposer struct {
 }
 
 type proposalBucket struct {
-	requestCount       uint32
+	// myConfig is consulted for the batch size on each use, as it may be
+	// updated while the bucket is active.
+	myConfig           *state.EventInitialParameters
 	pending            []*clientRequest
 	bucketID           bucketID
 	checkpointInterval uint64
@@ -59,7 +61,7 @@ func newProposer(baseCheckpoint uint64, checkpointInterval uint64, myConfig *sta
 			bucketID:           bucketID,
 			readyList:          list.New(),
 			nextReadyList:      list.New(),
-			requestCount:       myConfig.BatchSize,
+			myConfig:           myConfig,
 			pending:            make([]*clientRequest, 0, 1), // TODO, might be interesting to play with not preallocating for performance reasons
 		}
 	}
@@ -132,7 +134,7 @@ func (prb *proposalBucket) advance(toSeqNo uint64) {
 		prb.nextReadyList = list.New()
 	}
 
-	for uint32(len(prb.pending)) < prb.requestCount {
+	for uint32(len(prb.pending)) < prb.myConfig.BatchSize {
 		if prb.readyList.Len() == 0 {
 			break
 		}
@@ -151,11 +153,12 @@ func (prb *proposalBucket) hasOutstanding(forSeqNo uint64) bool {
 
 func (prb *proposalBucket) hasPending(forSeqNo uint64) bool {
 	prb.advance(forSeqNo)
-	return len(prb.pending) > 0 && uint32(len(prb.pending)) == prb.requestCount
+	// The batch size may have been reduced since the requests became pending.
+	return len(prb.pending) > 0 && uint32(len(prb.pending)) >= prb.myConfig.BatchSize
 }
 
 func (prb *proposalBucket) next() []*clientRequest {
 	result := prb.pending
-	prb.pending = make([]*clientRequest, 0, prb.requestCount)
+	prb.pending = make([]*clientRequest, 0, prb.myConfig.BatchSize)
 	return result
 }
@@ -80,7 +80,7 @@ func newSequence(owner nodeID, epoch, seqNo uint64, persisted *persisted, networ
 		seqNo:         seqNo,
 		epoch:         epoch,
 		myConfig:      myConfig,
-		logger:        logger,
+		logger:        componentLogger(logger, "sequence"),
 		networkConfig: networkConfig,
 		persisted:     persisted,
 		state:         sequenceUninitialized,
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
 	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
@@ -93,13 +94,27 @@ const (
 	smInitialized
 )
 
+// RequestTracer is notified of request transitions which are not otherwise
+// visible in the actions of the state machine.  It must not modify the acks.
+type RequestTracer interface {
+	// RequestCorrect is invoked once a weak quorum has acked the request.
+	RequestCorrect(ack *msgs.RequestAck)
+
+	// RequestStrong is invoked once an intersection quorum has acked the request.
+	RequestStrong(ack *msgs.RequestAck)
+}
+
 // StateMachine contains a deterministic processor for state events.
 // This structure should almost never be initialized directly but should instead
 // be allocated via StartNode.
 type StateMachine struct {
 	Logger Logger
 
-	state stateMachineState
+	// RequestTracer, if non-nil, is notified as requests become correct and strong.
+	RequestTracer RequestTracer
+
+	logger Logger
+	state  stateMachineState
 
 	myConfig               *state.EventInitialParameters
 	commitState            *commitState
@@ -116,7 +131,9 @@ type StateMachine struct {
 func (sm *StateMachine) initialize(parameters *state.EventInitialParameters) {
 	assertEqualf(sm.state, smUninitialized, "state machine has already been initialized")
 
-	sm.myConfig = parameters
+	// The parameters are copied, as they may later be updated in place.
+	sm.myConfig = proto.Clone(parameters).(*state.EventInitialParameters)
+	sm.logger = componentLogger(sm.Logger, "state_machine")
 	sm.state = smLoadingPersisted
 	sm.persisted = newPersisted(sm.Logger)
 
@@ -135,7 +152,7 @@ func (sm *StateMachine) initialize(parameters *state.EventInitialParameters) {
 	sm.checkpointTracker = newCheckpointTracker(0, dummyInitialState, sm.persisted, sm.nodeBuffers, sm.myConfig, sm.Logger)
 	sm.clientTracker = newClientTracker(sm.myConfig, sm.Logger)
 	sm.commitState = newCommitState(sm.persisted, sm.Logger)
-	sm.clientHashDisseminator = newClientHashDisseminator(sm.nodeBuffers, sm.myConfig, sm.Logger, sm.clientTracker)
+	sm.clientHashDisseminator = newClientHashDisseminator(sm.nodeBuffers, sm.myConfig, sm.Logger, sm.RequestTracer, sm.clientTracker)
 	sm.batchTracker = newBatchTracker(sm.persisted)
 	sm.epochTracker = newEpochTracker(
 		sm.persisted,
@@ -151,6 +168,25 @@ func (sm *StateMachine) initialize(parameters *state.EventInitialParameters) {
 
 }
 
+// updateParameters replaces the local tunables in place, as the components
+// of the state machine share and consult the same parameters.  Changes take
+// effect at their next use, for instance the next batch cut or tick.
+func (sm *StateMachine) updateParameters(parameters *state.EventUpdateParameters) {
+	sm.logger.Log(LevelInfo, "updating parameters",
+		"batch_size", parameters.BatchSize,
+		"heartbeat_ticks", parameters.HeartbeatTicks,
+		"suspect_ticks", parameters.SuspectTicks,
+		"new_epoch_timeout_ticks", parameters.NewEpochTimeoutTicks,
+		"buffer_size", parameters.BufferSize,
+	)
+
+	sm.myConfig.BatchSize = parameters.BatchSize
+	sm.myConfig.HeartbeatTicks = parameters.HeartbeatTicks
+	sm.myConfig.SuspectTicks = parameters.SuspectTicks
+	sm.myConfig.NewEpochTimeoutTicks = parameters.NewEpochTimeoutTicks
+	sm.myConfig.BufferSize = parameters.BufferSize
+}
+
 func (sm *StateMachine) applyPersisted(index uint64, data *msgs.Persistent) {
 	assertEqualf(sm.state, smLoadingPersisted, "state machine has already finished loading persisted data")
 	sm.persisted.appendInitialLoad(index, data)
@@ -208,12 +244,12 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 			event.RequestPersisted.RequestAck,
 		))
 	case *state.Event_StateTransferFailed:
-		sm.Logger.Log(LevelDebug, "state transfer failed", "seq_no", event.StateTransferFailed.SeqNo)
+		sm.logger.Log(LevelDebug, "state transfer failed", "seq_no", event.StateTransferFailed.SeqNo)
 		panic("XXX handle state transfer failure")
 	case *state.Event_StateTransferComplete:
 		assertEqualf(sm.commitState.transferring, true, "state transfer event received but the state machine did not request transfer")
 
-		sm.Logger.Log(LevelDebug, "state transfer completed", "seq_no", event.StateTransferComplete.SeqNo)
+		sm.logger.Log(LevelDebug, "state transfer completed", "seq_no", event.StateTransferComplete.SeqNo)
 
 		actions.concat(sm.persisted.addCEntry(&msgs.CEntry{
 			SeqNo:           event.StateTransferComplete.SeqNo,
@@ -221,6 +257,10 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 			NetworkState:    event.StateTransferComplete.NetworkState,
 		}))
 		actions.concat(sm.reinitialize())
+	case *state.Event_UpdateParameters:
+		assertInitialized()
+		sm.updateParameters(event.UpdateParameters)
+		return &ActionList{}
 	case *state.Event_ActionsReceived:
 		// This is a bit odd, in that it's a no-op, but it's harmless
 		// and allows for much more insightful playback events (allowing
@@ -238,7 +278,7 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 	// the next checkpoint.)
 	if sm.checkpointTracker.state == cpsGarbageCollectable {
 		newLow := sm.checkpointTracker.garbageCollect()
-		sm.Logger.Log(LevelDebug, "garbage collecting through", "seq_no", newLow)
+		sm.logger.Log(LevelDebug, "garbage collecting through", "seq_no", newLow)
 
 		sm.persisted.truncate(newLow)
 
@@ -274,7 +314,7 @@ func (sm *StateMachine) applyEvent(stateEvent *state.Event) *ActionList {
 // the clientTracker retains in-window ACKs for still-extant clients.  The checkpointTracker
 // retains checkpoint messages sent by other replicas, etc.
 func (sm *StateMachine) reinitialize() *ActionList {
-	defer sm.Logger.Log(LevelInfo, "state machine reinitialized (either due to start, state transfer, or reconfiguration)")
+	defer sm.logger.Log(LevelInfo, "state machine reinitialized (either due to start, state transfer, or reconfiguration)")
 
 	actions := sm.recoverLog()
 	actions.concat(sm.commitState.reinitialize())
@@ -400,6 +440,45 @@ func (sm *StateMachine) processCheckpointResult(checkpointResult *state.EventChe
 	return actions
 }
 
+// CurrentEpoch returns the number of the epoch the state machine is currently
+// operating in, or attempting to change to.  It is considerably cheaper than Status.
+func (sm *StateMachine) CurrentEpoch() uint64 {
+	if sm.state != smInitialized {
+		return 0
+	}
+
+	return sm.epochTracker.currentEpoch.number
+}
+
+// BufferSizes returns the total bytes of messages buffered from each node.
+// It is considerably cheaper than Status.
+func (sm *StateMachine) BufferSizes() map[uint64]int {
+	if sm.state != smInitialized {
+		return map[uint64]int{}
+	}
+
+	return sm.nodeBuffers.sizes()
+}
+
+// Facets returns a summary of the epoch, watermarks, and checkpoint stability
+// of the state machine.  It is considerably cheaper than Status.
+func (sm *StateMachine) Facets() *status.Facets {
+	if sm.state != smInitialized {
+		return &status.Facets{}
+	}
+
+	currentEpoch := sm.epochTracker.currentEpoch
+	lowWatermark, highWatermark := currentEpoch.watermarks()
+
+	return &status.Facets{
+		EpochNumber:      currentEpoch.number,
+		EpochState:       status.EpochTargetState(currentEpoch.state),
+		LowWatermark:     lowWatermark,
+		HighWatermark:    highWatermark,
+		StableCheckpoint: sm.checkpointTracker.highestStable(),
+	}
+}
+
 func (sm *StateMachine) Status() (s *status.StateMachine, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -434,5 +513,7 @@ func (sm *StateMachine) Status() (s *status.StateMachine, err error) {
 		Buckets:       bucketStatus,
 		Checkpoints:   checkpoints,
 		NodeBuffers:   sm.nodeBuffers.status(),
+		BatchFetches:  sm.batchTracker.status(),
+		CommitState:   sm.commitState.status(),
 	}, nil
 }
@@ -0,0 +1,298 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Comparison lines up the statuses of several nodes.  The per-node slices
+// of each entry are indexed in the same order as Nodes.
+type Comparison struct {
+	Nodes       []*NodeSummary          `json:"nodes"`
+	Checkpoints []*CheckpointComparison `json:"checkpoints"`
+	Sequences   []*SequenceComparison   `json:"sequences"`
+
+	// EpochsAgree is false if the nodes report different active epochs.
+	EpochsAgree bool `json:"epochs_agree"`
+
+	// Findings are human readable descriptions of the problems detected.
+	Findings []string `json:"findings"`
+}
+
+// NodeSummary is the overview of a single node's status.
+type NodeSummary struct {
+	NodeID           uint64           `json:"node_id"`
+	LowWatermark     uint64           `json:"low_watermark"`
+	HighWatermark    uint64           `json:"high_watermark"`
+	Epoch            uint64           `json:"epoch"`
+	EpochState       EpochTargetState `json:"epoch_state"`
+	StableCheckpoint uint64           `json:"stable_checkpoint"`
+
+	// Behind is the number of sequences by which this node's low watermark
+	// trails the highest low watermark among the nodes.
+	Behind uint64 `json:"behind"`
+}
+
+// CheckpointComparison is the local value of a checkpoint at each node, nil
+// if the node has not computed it.
+type CheckpointComparison struct {
+	SeqNo    uint64   `json:"seq_no"`
+	Values   [][]byte `json:"values"`
+	Diverged bool     `json:"diverged"`
+}
+
+// SequenceComparison is the state of a sequence at each node.  Nodes whose
+// watermarks do not include the sequence report SequenceUninitialized.
+type SequenceComparison struct {
+	SeqNo  uint64          `json:"seq_no"`
+	States []SequenceState `json:"states"`
+}
+
+// Compare lines up the checkpoints, watermarks, sequence states and epochs of
+// the given statuses, which are ordered by node ID.  Nil statuses are ignored.
+func Compare(statuses ...*StateMachine) *Comparison {
+	sorted := make([]*StateMachine, 0, len(statuses))
+	for _, s := range statuses {
+		if s != nil {
+			sorted = append(sorted, s)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].NodeID < sorted[j].NodeID
+	})
+
+	c := &Comparison{
+		Nodes:       make([]*NodeSummary, len(sorted)),
+		Checkpoints: []*CheckpointComparison{},
+		Sequences:   []*SequenceComparison{},
+		EpochsAgree: true,
+		Findings:    []string{},
+	}
+
+	if len(sorted) == 0 {
+		return c
+	}
+
+	var maxLowWatermark uint64
+	minLowWatermark, maxHighWatermark := sorted[0].LowWatermark, sorted[0].HighWatermark
+	for i, s := range sorted {
+		summary := &NodeSummary{
+			NodeID:        s.NodeID,
+			LowWatermark:  s.LowWatermark,
+			HighWatermark: s.HighWatermark,
+		}
+		if s.EpochTracker != nil && s.EpochTracker.ActiveEpoch != nil {
+			summary.Epoch = s.EpochTracker.ActiveEpoch.Number
+			summary.EpochState = s.EpochTracker.ActiveEpoch.State
+		}
+		for _, cp := range s.Checkpoints {
+			if cp.NetQuorum && cp.LocalDecision && cp.SeqNo > summary.StableCheckpoint {
+				summary.StableCheckpoint = cp.SeqNo
+			}
+		}
+		c.Nodes[i] = summary
+
+		if s.LowWatermark > maxLowWatermark {
+			maxLowWatermark = s.LowWatermark
+		}
+		if s.LowWatermark < minLowWatermark {
+			minLowWatermark = s.LowWatermark
+		}
+		if s.HighWatermark > maxHighWatermark {
+			maxHighWatermark = s.HighWatermark
+		}
+	}
+
+	for _, summary := range c.Nodes {
+		summary.Behind = maxLowWatermark - summary.LowWatermark
+		if summary.Behind > 0 {
+			c.Findings = append(c.Findings, fmt.Sprintf("node %d is behind by %d sequences (low watermark %d, highest %d)", summary.NodeID, summary.Behind, summary.LowWatermark, maxLowWatermark))
+		}
+	}
+
+	c.compareCheckpoints(sorted)
+	c.compareEpochs()
+	c.compareSequences(sorted, minLowWatermark, maxHighWatermark)
+
+	return c
+}
+
+func (c *Comparison) compareCheckpoints(sorted []*StateMachine) {
+	bySeqNo := map[uint64]*CheckpointComparison{}
+	for i, s := range sorted {
+		for _, cp := range s.Checkpoints {
+			cc, ok := bySeqNo[cp.SeqNo]
+			if !ok {
+				cc = &CheckpointComparison{
+					SeqNo:  cp.SeqNo,
+					Values: make([][]byte, len(sorted)),
+				}
+				bySeqNo[cp.SeqNo] = cc
+				c.Checkpoints = append(c.Checkpoints, cc)
+			}
+			cc.Values[i] = cp.Value
+		}
+	}
+
+	sort.Slice(c.Checkpoints, func(i, j int) bool {
+		return c.Checkpoints[i].SeqNo < c.Checkpoints[j].SeqNo
+	})
+
+	for _, cc := range c.Checkpoints {
+		var first []byte
+		for _, value := range cc.Values {
+			if value == nil {
+				continue
+			}
+			if first == nil {
+				first = value
+				continue
+			}
+			if !bytes.Equal(first, value) {
+				cc.Diverged = true
+			}
+		}
+
+		if !cc.Diverged {
+			continue
+		}
+
+		values := []string{}
+		for i, value := range cc.Values {
+			if value != nil {
+				values = append(values, fmt.Sprintf("node %d=%.4x", c.Nodes[i].NodeID, value))
+			}
+		}
+		c.Findings = append(c.Findings, fmt.Sprintf("checkpoint %d values differ: %s", cc.SeqNo, strings.Join(values, ", ")))
+	}
+}
+
+func (c *Comparison) compareEpochs() {
+	var epochs []uint64
+	byEpoch := map[uint64][]uint64{}
+	for _, summary := range c.Nodes {
+		if _, ok := byEpoch[summary.Epoch]; !ok {
+			epochs = append(epochs, summary.Epoch)
+		}
+		byEpoch[summary.Epoch] = append(byEpoch[summary.Epoch], summary.NodeID)
+	}
+
+	if len(epochs) <= 1 {
+		return
+	}
+
+	c.EpochsAgree = false
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+	groups := make([]string, len(epochs))
+	for i, epoch := range epochs {
+		groups[i] = fmt.Sprintf("epoch %d %v", epoch, byEpoch[epoch])
+	}
+	c.Findings = append(c.Findings, fmt.Sprintf("nodes disagree on the active epoch: %s", strings.Join(groups, ", ")))
+}
+
+func (c *Comparison) compareSequences(sorted []*StateMachine, lowWatermark, highWatermark uint64) {
+	if highWatermark < lowWatermark || highWatermark-lowWatermark > 10000 {
+		// Either there is nothing to compare, or the watermarks are suspiciously
+		// wide, and this is already reported by Pretty for the individual nodes.
+		return
+	}
+
+	for seqNo := lowWatermark; seqNo <= highWatermark; seqNo++ {
+		c.Sequences = append(c.Sequences, &SequenceComparison{
+			SeqNo:  seqNo,
+			States: make([]SequenceState, len(sorted)),
+		})
+	}
+
+	for i, s := range sorted {
+		numBuckets := uint64(len(s.Buckets))
+		for _, bucket := range s.Buckets {
+			for column, state := range bucket.Sequences {
+				// Each column covers numBuckets consecutive sequences, of
+				// which this bucket owns the one congruent to its ID.
+				columnStart := s.LowWatermark + uint64(column)*numBuckets
+				seqNo := columnStart + (bucket.ID+numBuckets-columnStart%numBuckets)%numBuckets
+				if seqNo < lowWatermark || seqNo > highWatermark || seqNo > s.HighWatermark {
+					continue
+				}
+				c.Sequences[seqNo-lowWatermark].States[i] = state
+			}
+		}
+	}
+}
+
+// Pretty renders the comparison as text.
+func (c *Comparison) Pretty() string {
+	var buffer bytes.Buffer
+
+	fmt.Fprintf(&buffer, "=== Nodes ===\n")
+	for _, summary := range c.Nodes {
+		fmt.Fprintf(&buffer, "Node %3d  LowWatermark=%-6d HighWatermark=%-6d Epoch=%-4d EpochState=%d StableCheckpoint=%-6d Behind=%d\n",
+			summary.NodeID, summary.LowWatermark, summary.HighWatermark, summary.Epoch, summary.EpochState, summary.StableCheckpoint, summary.Behind)
+	}
+
+	fmt.Fprintf(&buffer, "\n=== Checkpoints ===\n")
+	for _, cc := range c.Checkpoints {
+		fmt.Fprintf(&buffer, "SeqNo=%-6d", cc.SeqNo)
+		for i, value := range cc.Values {
+			if value == nil {
+				fmt.Fprintf(&buffer, " %d=--------", c.Nodes[i].NodeID)
+				continue
+			}
+			fmt.Fprintf(&buffer, " %d=%.4x", c.Nodes[i].NodeID, value)
+		}
+		if cc.Diverged {
+			fmt.Fprintf(&buffer, " DIVERGED")
+		}
+		fmt.Fprintf(&buffer, "\n")
+	}
+
+	fmt.Fprintf(&buffer, "\n=== Sequences ===\n")
+	for i, summary := range c.Nodes {
+		fmt.Fprintf(&buffer, "Node %3d ", summary.NodeID)
+		for _, sc := range c.Sequences {
+			fmt.Fprintf(&buffer, "%c", sequenceStateRune(sc.States[i]))
+		}
+		fmt.Fprintf(&buffer, "\n")
+	}
+
+	fmt.Fprintf(&buffer, "\n=== Findings ===\n")
+	if len(c.Findings) == 0 {
+		fmt.Fprintf(&buffer, "None\n")
+	}
+	for _, finding := range c.Findings {
+		fmt.Fprintf(&buffer, "%s\n", finding)
+	}
+
+	return buffer.String()
+}
+
+// sequenceStateRune uses the same abbreviations as StateMachine.Pretty.
+func sequenceStateRune(state SequenceState) rune {
+	switch state {
+	case SequenceAllocated:
+		return 'A'
+	case SequencePendingRequests:
+		return 'F'
+	case SequenceReady:
+		return 'R'
+	case SequencePreprepared:
+		return 'Q'
+	case SequencePrepared:
+		return 'P'
+	case SequenceCommitted:
+		return 'C'
+	default:
+		return '.'
+	}
+}
@@ -34,16 +34,51 @@ const (
 	// EpochReadying indicates we have received a quorum of echos, waiting a on qourum of readies
 	EpochReadying
 
+	// EpochResuming indicates we crashed during this epoch, and are waiting to resume
+	EpochResuming
+
 	// EpochReady indicates the new epoch is ready to begin
 	EpochReady
 
 	// EpochInProgress indicates the epoch is currently active
 	EpochInProgress
 
+	// EpochEnding indicates the epoch has committed everything it can, and has a stable checkpoint
+	EpochEnding
+
 	// EpochDone indicates this epoch has ended, either gracefully or because we sent an epoch change
 	EpochDone
 )
 
+func (s EpochTargetState) String() string {
+	switch s {
+	case EpochPrepending:
+		return "prepending"
+	case EpochPending:
+		return "pending"
+	case EpochVerifying:
+		return "verifying"
+	case EpochFetching:
+		return "fetching"
+	case EpochEchoing:
+		return "echoing"
+	case EpochReadying:
+		return "readying"
+	case EpochResuming:
+		return "resuming"
+	case EpochReady:
+		return "ready"
+	case EpochInProgress:
+		return "in_progress"
+	case EpochEnding:
+		return "ending"
+	case EpochDone:
+		return "done"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
 type SequenceState int
 
 const (
@@ -71,6 +106,15 @@ const (
 	SequenceCommitted
 )
 
+// Facets summarizes the most frequently watched portions of the status.
+type Facets struct {
+	EpochNumber      uint64           `json:"epoch_number"`
+	EpochState       EpochTargetState `json:"epoch_state"`
+	LowWatermark     uint64           `json:"low_watermark"`
+	HighWatermark    uint64           `json:"high_watermark"`
+	StableCheckpoint uint64           `json:"stable_checkpoint"`
+}
+
 type StateMachine struct {
 	NodeID        uint64           `json:"node_id"`
 	LowWatermark  uint64           `json:"low_watermark"`
@@ -80,6 +124,39 @@ type StateMachine struct {
 	Buckets       []*Bucket        `json:"buckets"`
 	Checkpoints   []*Checkpoint    `json:"checkpoints"`
 	ClientWindows []*ClientTracker `json:"client_tracker"`
+	BatchFetches  []*BatchFetch    `json:"batch_fetches"`
+	CommitState   *CommitState     `json:"commit_state"`
+}
+
+// BatchFetch is a batch which is being fetched from other nodes, for instance
+// during an epoch change.
+type BatchFetch struct {
+	Digest []byte   `json:"digest"`
+	SeqNos []uint64 `json:"seq_nos"`
+}
+
+// CommitState describes the progress of commits towards the application,
+// including any state transfer which is in progress.
+type CommitState struct {
+	LowWatermark            uint64             `json:"low_watermark"`
+	LastAppliedCommit       uint64             `json:"last_applied_commit"`
+	HighestCommit           uint64             `json:"highest_commit"`
+	StopAtSeqNo             uint64             `json:"stop_at_seq_no"`
+	CheckpointPending       bool               `json:"checkpoint_pending"`
+	Transferring            bool               `json:"transferring"`
+	PendingReconfigurations []*Reconfiguration `json:"pending_reconfigurations"`
+}
+
+// Reconfiguration is a reconfiguration which has committed, but which does
+// not take effect until a later checkpoint.  Type is one of "new_client",
+// "remove_client", or "new_config".
+type Reconfiguration struct {
+	Type            string   `json:"type"`
+	ClientID        uint64   `json:"client_id,omitempty"`
+	Width           uint32   `json:"width,omitempty"`
+	Nodes           []uint64 `json:"nodes,omitempty"`
+	F               int32    `json:"f,omitempty"`
+	NumberOfBuckets int32    `json:"number_of_buckets,omitempty"`
 }
 
 type Bucket struct {
@@ -93,6 +170,7 @@ type Checkpoint struct {
 	MaxAgreements int    `json:"max_agreements"`
 	NetQuorum     bool   `json:"net_quorum"`
 	LocalDecision bool   `json:"local_decision"`
+	Value         []byte `json:"value,omitempty"` // The locally computed value, if any
 }
 
 type EpochTracker struct {
@@ -160,10 +238,34 @@ type ClientTracker struct {
 	LowWatermark  uint64   `json:"low_watermark"`
 	HighWatermark uint64   `json:"high_watermark"`
 	Allocated     []uint64 `json:"allocated"`
+	ReqNos        []*ReqNo `json:"req_nos"`
+}
+
+// ReqNo describes the requests observed for a client request number which
+// has not yet committed.
+type ReqNo struct {
+	ReqNo    uint64     `json:"req_no"`
+	AcksSent uint       `json:"acks_sent"`
+	Requests []*Request `json:"requests"`
+}
+
+// Request describes a request (identified by its digest, which is empty for
+// the null request) and the number of nodes which have acknowledged it.
+type Request struct {
+	Digest        []byte `json:"digest"`
+	Acks          int    `json:"acks"`
+	Stored        bool   `json:"stored"`
+	Fetching      bool   `json:"fetching"`
+	TicksFetching uint   `json:"ticks_fetching"`
 }
 
 func (s *StateMachine) Pretty() string {
 	var buffer bytes.Buffer
+	if s.EpochTracker == nil || s.EpochTracker.ActiveEpoch == nil {
+		fmt.Fprintf(&buffer, "=== State machine not initialized ===\n")
+		return buffer.String()
+	}
+
 	fmt.Fprintf(&buffer, "===========================================\n")
 	fmt.Fprintf(&buffer, "NodeID=%d, LowWatermark=%d, HighWatermark=%d, Epoch=%d\n", s.NodeID, s.LowWatermark, s.HighWatermark, s.EpochTracker.ActiveEpoch.Number)
 	fmt.Fprintf(&buffer, "===========================================\n\n")
@@ -186,8 +288,14 @@ func (s *StateMachine) Pretty() string {
 	fmt.Fprintf(&buffer, "=====================\n")
 	fmt.Fprintf(&buffer, "\n")
 
+	// Each column covers one sequence per bucket.
+	columnWidth := uint64(len(s.Buckets))
+	if columnWidth == 0 {
+		columnWidth = 1
+	}
+
 	hRule := func() {
-		for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += uint64(len(s.Buckets)) {
+		for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += columnWidth {
 			fmt.Fprintf(&buffer, "--")
 		}
 	}
@@ -202,7 +310,7 @@ func (s *StateMachine) Pretty() string {
 
 		for i := len(fmt.Sprintf("%d", s.HighWatermark)); i > 0; i-- {
 			magnitude := math.Pow10(i - 1)
-			for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += uint64(len(s.Buckets)) {
+			for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += columnWidth {
 				fmt.Fprintf(&buffer, " %d", seqNo/uint64(magnitude)%10)
 			}
 			fmt.Fprintf(&buffer, "\n")
@@ -240,7 +348,7 @@ func (s *StateMachine) Pretty() string {
 		hRule()
 		fmt.Fprintf(&buffer, "- === Checkpoints ===\n")
 		i := 0
-		for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += uint64(len(s.Buckets)) {
+		for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += columnWidth {
 			if len(s.Checkpoints) > i {
 				checkpoint := s.Checkpoints[i]
 				if seqNo == checkpoint.SeqNo {
@@ -253,10 +361,10 @@ func (s *StateMachine) Pretty() string {
 		}
 		fmt.Fprintf(&buffer, "| Max Agreements\n")
 		i = 0
-		for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += uint64(len(s.Buckets)) {
+		for seqNo := s.LowWatermark; seqNo <= s.HighWatermark; seqNo += columnWidth {
 			if len(s.Checkpoints) > i {
 				checkpoint := s.Checkpoints[i]
-				if seqNo == s.Checkpoints[i].SeqNo/uint64(len(s.Buckets)) {
+				if seqNo == s.Checkpoints[i].SeqNo/columnWidth {
 					switch {
 					case checkpoint.NetQuorum && !checkpoint.LocalDecision:
 						fmt.Fprintf(&buffer, "|N")
@@ -283,9 +391,47 @@ func (s *StateMachine) Pretty() string {
 	hRule()
 	for _, rws := range s.ClientWindows {
 		fmt.Fprintf(&buffer, "\nClient %x L/H %d/%d : %v\n", rws.ClientID, rws.LowWatermark, rws.HighWatermark, rws.Allocated)
+		for _, reqNo := range rws.ReqNos {
+			fmt.Fprintf(&buffer, "  ReqNo=%d AcksSent=%d\n", reqNo.ReqNo, reqNo.AcksSent)
+			for _, request := range reqNo.Requests {
+				fmt.Fprintf(&buffer, "    Digest=%.4x Acks=%d Stored=%t Fetching=%t", request.Digest, request.Acks, request.Stored, request.Fetching)
+				if request.Fetching {
+					fmt.Fprintf(&buffer, " TicksFetching=%d", request.TicksFetching)
+				}
+				fmt.Fprintf(&buffer, "\n")
+			}
+		}
 		hRule()
 	}
 
+	fmt.Fprintf(&buffer, "\n\n Batch Fetches\n")
+	hRule()
+	fmt.Fprintf(&buffer, "\n")
+	if len(s.BatchFetches) == 0 {
+		fmt.Fprintf(&buffer, "  None\n")
+	}
+	for _, fetch := range s.BatchFetches {
+		fmt.Fprintf(&buffer, "  Digest=%.4x SeqNos=%v\n", fetch.Digest, fetch.SeqNos)
+	}
+
+	if cs := s.CommitState; cs != nil {
+		fmt.Fprintf(&buffer, "\n\n Commit State\n")
+		hRule()
+		fmt.Fprintf(&buffer, "\n")
+		fmt.Fprintf(&buffer, "  LowWatermark=%d LastAppliedCommit=%d HighestCommit=%d StopAtSeqNo=%d\n", cs.LowWatermark, cs.LastAppliedCommit, cs.HighestCommit, cs.StopAtSeqNo)
+		fmt.Fprintf(&buffer, "  CheckpointPending=%t Transferring=%t\n", cs.CheckpointPending, cs.Transferring)
+		for _, reconfig := range cs.PendingReconfigurations {
+			switch reconfig.Type {
+			case "new_client":
+				fmt.Fprintf(&buffer, "  PendingReconfiguration=new_client ClientID=%d Width=%d\n", reconfig.ClientID, reconfig.Width)
+			case "remove_client":
+				fmt.Fprintf(&buffer, "  PendingReconfiguration=remove_client ClientID=%d\n", reconfig.ClientID)
+			default:
+				fmt.Fprintf(&buffer, "  PendingReconfiguration=%s Nodes=%v F=%d NumberOfBuckets=%d\n", reconfig.Type, reconfig.Nodes, reconfig.F, reconfig.NumberOfBuckets)
+			}
+		}
+	}
+
 	fmt.Fprintf(&buffer, "\n\n Message Buffers\n")
 	hRule()
 
@@ -0,0 +1,541 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"container/list"
+	"io"
+	"math/rand"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/recording"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/processor"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// Continuation follows a recording event by event, reconstructing what the
+// testengine would hold for each node, so that the recording may be
+// continued as a simulation from any point.  The WAL, request store,
+// clients, and application state of each node are rebuilt by processing the
+// recorded actions, and the results of that processing and the messages
+// sent, which the recording has not yet shown to be delivered, are retained
+// for the simulation to deliver.
+//
+// The application and request data are those of the testengine, so
+// recordings made by the testengine are continued faithfully, while other
+// recordings are continued with placeholder request data and checkpoint
+// values of the testengine's making.
+type Continuation struct {
+	Hasher processor.Hasher
+
+	time     int64
+	nodes    map[uint64]*continuedNode
+	inFlight *list.List
+	sent     map[faultKey][]*list.Element
+	reqNos   map[uint64]uint64
+}
+
+type continuedNode struct {
+	initParms    *state.EventInitialParameters
+	loaded       []*state.EventLoadPersistedEntry
+	ticked       bool
+	lastTick     int64
+	tickInterval int64
+	wal          *WAL
+	reqStore     *ReqStore
+	state        *NodeState
+	clients      *processor.Clients
+	results      []*state.Event
+}
+
+type inFlightMsg struct {
+	source uint64
+	target uint64
+	msg    *msgs.Msg
+	key    faultKey
+}
+
+// ContinuedNode is a node's state machine at the point of continuation,
+// along with the actions it produced since its last ActionsReceived event.
+type ContinuedNode struct {
+	StateMachine   *statemachine.StateMachine
+	PendingActions *statemachine.ActionList
+}
+
+func NewContinuation(hasher processor.Hasher) *Continuation {
+	return &Continuation{
+		Hasher:   hasher,
+		nodes:    map[uint64]*continuedNode{},
+		inFlight: list.New(),
+		sent:     map[faultKey][]*list.Element{},
+		reqNos:   map[uint64]uint64{},
+	}
+}
+
+// Observe advances the continuation past a recorded event.  For
+// ActionsReceived events, actions must be those the node's state machine
+// produced since its previous ActionsReceived event, for other events they
+// are ignored.
+func (c *Continuation) Observe(event *recording.Event, actions *statemachine.ActionList) error {
+	c.time = event.Time
+
+	node, ok := c.nodes[event.NodeId]
+	if !ok {
+		if _, ok := event.StateEvent.Type.(*state.Event_Initialize); !ok {
+			return errors.Errorf("node %d has event of type %T before initializing", event.NodeId, event.StateEvent.Type)
+		}
+		node = &continuedNode{
+			reqStore: NewReqStore(),
+		}
+		c.nodes[event.NodeId] = node
+	}
+
+	switch et := event.StateEvent.Type.(type) {
+	case *state.Event_Initialize:
+		// As in the testengine, a restarted node loses its clients, its
+		// outstanding work, and the messages in flight to it.
+		node.initParms = et.Initialize
+		node.loaded = nil
+		node.results = nil
+		node.clients = &processor.Clients{
+			RequestStore: node.reqStore,
+			Hasher:       c.Hasher,
+		}
+		c.dropInFlight(event.NodeId)
+	case *state.Event_LoadPersistedEntry:
+		node.loaded = append(node.loaded, et.LoadPersistedEntry)
+	case *state.Event_CompleteInitialization:
+		return c.completeInitialization(event.NodeId, node)
+	case *state.Event_TickElapsed:
+		if node.ticked {
+			node.tickInterval = event.Time - node.lastTick
+		}
+		node.ticked = true
+		node.lastTick = event.Time
+	case *state.Event_Step:
+		return c.delivered(et.Step.Source, event.NodeId, et.Step.Msg)
+	case *state.Event_RequestPersisted:
+		ack := et.RequestPersisted.RequestAck
+		if ack.ReqNo >= c.reqNos[ack.ClientId] {
+			c.reqNos[ack.ClientId] = ack.ReqNo + 1
+		}
+		if !node.consumeResult(event.StateEvent) {
+			return c.persisted(node, ack)
+		}
+	case *state.Event_HashResult, *state.Event_CheckpointResult, *state.Event_StateTransferComplete, *state.Event_StateTransferFailed:
+		node.consumeResult(event.StateEvent)
+	case *state.Event_ActionsReceived:
+		return c.process(event.NodeId, node, actions)
+	}
+
+	return nil
+}
+
+// completeInitialization rebuilds the WAL from the loaded entries.  The
+// application state survives restarts, so it is only created from the
+// latest loaded checkpoint the first time the node initializes.
+func (c *Continuation) completeInitialization(nodeID uint64, node *continuedNode) error {
+	node.wal = &WAL{
+		List: list.New(),
+	}
+
+	var cEntry *msgs.CEntry
+	for i, loaded := range node.loaded {
+		if i == 0 {
+			node.wal.LowIndex = loaded.Index
+		}
+		node.wal.List.PushBack(loaded.Entry)
+
+		if entry, ok := loaded.Entry.Type.(*msgs.Persistent_CEntry); ok {
+			cEntry = entry.CEntry
+		}
+	}
+	node.loaded = nil
+
+	if node.state != nil {
+		return nil
+	}
+
+	if cEntry == nil {
+		return errors.Errorf("node %d completed initialization without loading a checkpoint", nodeID)
+	}
+
+	// The testengine prefixes checkpoint values with the hash of the
+	// application state.
+	checkpointHash := cEntry.CheckpointValue
+	if size := c.Hasher.New().Size(); len(checkpointHash) > size {
+		checkpointHash = checkpointHash[:size]
+	}
+
+	node.state = &NodeState{
+		Hasher:          c.Hasher,
+		ActiveHash:      c.Hasher.New(),
+		LastSeqNo:       cEntry.SeqNo,
+		ReqStore:        node.reqStore,
+		CheckpointSeqNo: cEntry.SeqNo,
+		CheckpointHash:  checkpointHash,
+		CheckpointState: cEntry.NetworkState,
+	}
+	node.state.ActiveHash.Write(checkpointHash)
+
+	return nil
+}
+
+// process applies the actions the node's processing received, as the
+// testengine would.
+func (c *Continuation) process(nodeID uint64, node *continuedNode, actions *statemachine.ActionList) error {
+	if node.state == nil {
+		return errors.Errorf("node %d received actions before completing initialization", nodeID)
+	}
+
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		single := &statemachine.ActionList{}
+		single.PushBack(action)
+
+		switch t := action.Type.(type) {
+		case *state.Action_Send:
+			if err := c.send(nodeID, t.Send.Targets, t.Send.Msg); err != nil {
+				return err
+			}
+		case *state.Action_AppendWriteAhead, *state.Action_TruncateWriteAhead:
+			if _, err := processor.ProcessWALActions(node.wal, single); err != nil {
+				return errors.WithMessagef(err, "node %d could not process WAL actions", nodeID)
+			}
+		case *state.Action_Hash:
+			results, err := processor.ProcessHashActions(c.Hasher, single)
+			if err != nil {
+				return errors.WithMessagef(err, "node %d could not process hash actions", nodeID)
+			}
+			node.addResults(results)
+		case *state.Action_Commit, *state.Action_Checkpoint, *state.Action_StateTransfer:
+			if commit, ok := t.(*state.Action_Commit); ok {
+				if err := c.storeCommitted(node, commit.Commit.Batch); err != nil {
+					return err
+				}
+			}
+			results, err := processor.ProcessAppActions(node.state, single)
+			if err != nil {
+				return errors.WithMessagef(err, "node %d could not process app actions", nodeID)
+			}
+			node.addResults(results)
+		case *state.Action_AllocatedRequest, *state.Action_CorrectRequest, *state.Action_StateApplied:
+			results, err := node.clients.ProcessClientActions(single)
+			if err != nil {
+				return errors.WithMessagef(err, "node %d could not process client actions", nodeID)
+			}
+			node.addResults(results)
+		}
+	}
+
+	return nil
+}
+
+// requestData returns the data of a request, and whether it was
+// reconstructed.  Requests not proposed by a testengine client are given
+// their digest as placeholder data.
+func (c *Continuation) requestData(ack *msgs.RequestAck) ([]byte, bool) {
+	data := ClientRequestData(ack.ClientId, ack.ReqNo)
+	h := c.Hasher.New()
+	h.Write(data)
+	if bytes.Equal(h.Sum(nil), ack.Digest) {
+		return data, true
+	}
+
+	return append([]byte{}, ack.Digest...), false
+}
+
+// persisted records a request the node persisted other than by processing
+// its actions, that is, as proposed by a client.
+func (c *Continuation) persisted(node *continuedNode, ack *msgs.RequestAck) error {
+	data, reconstructed := c.requestData(ack)
+	if reconstructed {
+		// The client tracks the next request number to propose, should it
+		// not accept the proposal the request is stored below all the same.
+		_, _ = node.clients.Client(ack.ClientId).Propose(ack.ReqNo, data)
+	}
+
+	if err := node.reqStore.PutRequest(ack, data); err != nil {
+		return errors.WithMessage(err, "could not store request")
+	}
+	return node.reqStore.PutAllocation(ack.ClientId, ack.ReqNo, ack.Digest)
+}
+
+// storeCommitted ensures the application finds the data of each committed
+// request, even if its proposal preceded the recording.
+func (c *Continuation) storeCommitted(node *continuedNode, batch *msgs.QEntry) error {
+	for _, ack := range batch.Requests {
+		data, err := node.reqStore.GetRequest(ack)
+		if err != nil {
+			return errors.WithMessage(err, "could not get request")
+		}
+		if data != nil {
+			continue
+		}
+
+		data, _ = c.requestData(ack)
+		if err := node.reqStore.PutRequest(ack, data); err != nil {
+			return errors.WithMessage(err, "could not store request")
+		}
+	}
+
+	return nil
+}
+
+func (node *continuedNode) addResults(events *statemachine.EventList) {
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		node.results = append(node.results, event)
+	}
+}
+
+// consumeResult removes the retained result the recorded event corresponds
+// to, returning false if there is none.
+func (node *continuedNode) consumeResult(event *state.Event) bool {
+	for i, result := range node.results {
+		if sameResult(result, event) {
+			node.results = append(node.results[:i], node.results[i+1:]...)
+			return true
+		}
+	}
+
+	return false
+}
+
+// sameResult compares results by what they are the result of, as the
+// recorded hashes and checkpoint values may not be of the testengine's
+// making.
+func sameResult(a, b *state.Event) bool {
+	switch at := a.Type.(type) {
+	case *state.Event_HashResult:
+		bt, ok := b.Type.(*state.Event_HashResult)
+		return ok && proto.Equal(at.HashResult.Origin, bt.HashResult.Origin)
+	case *state.Event_CheckpointResult:
+		bt, ok := b.Type.(*state.Event_CheckpointResult)
+		return ok && at.CheckpointResult.SeqNo == bt.CheckpointResult.SeqNo
+	case *state.Event_RequestPersisted:
+		bt, ok := b.Type.(*state.Event_RequestPersisted)
+		return ok && proto.Equal(at.RequestPersisted.RequestAck, bt.RequestPersisted.RequestAck)
+	case *state.Event_StateTransferComplete:
+		bt, ok := b.Type.(*state.Event_StateTransferComplete)
+		return ok && at.StateTransferComplete.SeqNo == bt.StateTransferComplete.SeqNo
+	case *state.Event_StateTransferFailed:
+		bt, ok := b.Type.(*state.Event_StateTransferFailed)
+		return ok && at.StateTransferFailed.SeqNo == bt.StateTransferFailed.SeqNo
+	default:
+		return false
+	}
+}
+
+func (c *Continuation) send(source uint64, targets []uint64, msg *msgs.Msg) error {
+	msgBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal message")
+	}
+
+	for _, target := range targets {
+		key := faultKey{source: source, target: target, msg: string(msgBytes)}
+		el := c.inFlight.PushBack(&inFlightMsg{
+			source: source,
+			target: target,
+			msg:    msg,
+			key:    key,
+		})
+		c.sent[key] = append(c.sent[key], el)
+	}
+
+	return nil
+}
+
+func (c *Continuation) delivered(source, target uint64, msg *msgs.Msg) error {
+	msgBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return errors.WithMessage(err, "could not marshal message")
+	}
+
+	key := faultKey{source: source, target: target, msg: string(msgBytes)}
+	sent := c.sent[key]
+	if len(sent) == 0 {
+		return nil
+	}
+
+	c.inFlight.Remove(sent[0])
+	if len(sent) == 1 {
+		delete(c.sent, key)
+	} else {
+		c.sent[key] = sent[1:]
+	}
+
+	return nil
+}
+
+func (c *Continuation) dropInFlight(target uint64) {
+	for key, sent := range c.sent {
+		if key.target != target {
+			continue
+		}
+		for _, el := range sent {
+			c.inFlight.Remove(el)
+		}
+		delete(c.sent, key)
+	}
+}
+
+// Recording creates a recording which continues from the last observed
+// event, using the given state machines, to which every observed event must
+// have been applied.  As in recordings made by a Recorder, the node and
+// client IDs must run from zero.
+func (c *Continuation) Recording(nodes map[uint64]*ContinuedNode, output *gzip.Writer, logOutput io.Writer) (*Recording, error) {
+	eventQueue := &EventQueue{
+		List:     list.New(),
+		Rand:     rand.New(rand.NewSource(0)),
+		FakeTime: c.time,
+	}
+
+	recordingNodes := make([]*Node, len(c.nodes))
+	for nodeID, cn := range c.nodes {
+		if nodeID >= uint64(len(c.nodes)) {
+			return nil, errors.Errorf("node IDs must run from zero, but found node %d of %d", nodeID, len(c.nodes))
+		}
+
+		if cn.state == nil {
+			return nil, errors.Errorf("node %d has not completed initialization", nodeID)
+		}
+
+		continued, ok := nodes[nodeID]
+		if !ok {
+			return nil, errors.Errorf("no state machine for node %d", nodeID)
+		}
+
+		runtimeParms := defaultRuntimeParameters()
+		if cn.tickInterval > 0 {
+			runtimeParms.TickInterval = int(cn.tickInterval)
+		}
+
+		node := &Node{
+			ID: nodeID,
+			Config: &NodeConfig{
+				InitParms:    cn.initParms,
+				RuntimeParms: runtimeParms,
+			},
+			WAL:      cn.wal,
+			ReqStore: cn.reqStore,
+			State:    cn.state,
+			Clients:  cn.clients,
+			Hasher:   c.Hasher,
+			Link: &Link{
+				EventQueue: eventQueue,
+				Source:     nodeID,
+				Delay:      int64(runtimeParms.LinkLatency),
+			},
+			Interceptor: &recordingInterceptor{
+				nodeID:        nodeID,
+				output:        output,
+				eventQueue:    eventQueue,
+				recordActions: true,
+			},
+			WorkItems:    processor.NewWorkItems(),
+			StateMachine: continued.StateMachine,
+		}
+
+		if continued.PendingActions != nil {
+			node.WorkItems.AddStateMachineResults(continued.PendingActions)
+		}
+		for _, result := range cn.results {
+			node.WorkItems.ResultEvents().PushBack(result)
+		}
+
+		recordingNodes[nodeID] = node
+	}
+
+	for el := c.inFlight.Front(); el != nil; el = el.Next() {
+		m := el.Value.(*inFlightMsg)
+		if m.target >= uint64(len(recordingNodes)) {
+			continue
+		}
+
+		if m.source == m.target {
+			recordingNodes[m.target].WorkItems.ResultEvents().Step(m.source, m.msg)
+			continue
+		}
+
+		eventQueue.InsertMsgReceived(m.target, m.source, m.msg, recordingNodes[m.source].Link.Delay)
+	}
+
+	var clients []*RecorderClient
+	if len(recordingNodes) > 0 {
+		networkState := recordingNodes[0].State.CheckpointState
+		clients = make([]*RecorderClient, len(networkState.Clients))
+		for _, clientState := range networkState.Clients {
+			if clientState.Id >= uint64(len(clients)) {
+				return nil, errors.Errorf("client IDs must run from zero, but found client %d of %d", clientState.Id, len(clients))
+			}
+
+			// The clients propose up to the highest request recorded, or
+			// the highest committed, should it have been proposed before
+			// the recording.
+			total := c.reqNos[clientState.Id]
+			for _, node := range recordingNodes {
+				for _, nodeClientState := range node.State.CheckpointState.Clients {
+					if nodeClientState.Id == clientState.Id && nodeClientState.LowWatermark > total {
+						total = nodeClientState.LowWatermark
+					}
+				}
+			}
+
+			clients[clientState.Id] = &RecorderClient{
+				Config: &ClientConfig{
+					ID:          clientState.Id,
+					MaxInFlight: int(networkState.Config.CheckpointInterval / 2),
+					Total:       total,
+				},
+				Hasher: c.Hasher,
+			}
+		}
+	}
+
+	r := &Recording{
+		Hasher:           c.Hasher,
+		EventQueue:       eventQueue,
+		Nodes:            recordingNodes,
+		Clients:          clients,
+		EventQueueOutput: output,
+		LogOutput:        logOutput,
+	}
+
+	for _, node := range recordingNodes {
+		cn := c.nodes[node.ID]
+		runtimeParms := node.Config.RuntimeParms
+
+		// Keep the node's ticks at the interval they were recorded at.
+		nextTick := int64(runtimeParms.TickInterval)
+		if cn.ticked {
+			nextTick -= c.time - cn.lastTick
+			if nextTick < 0 {
+				nextTick = 0
+			}
+		}
+		eventQueue.InsertTickEvent(node.ID, nextTick)
+
+		for _, clientState := range node.State.CheckpointState.Clients {
+			data := clients[int(clientState.Id)].RequestByReqNo(clientState.LowWatermark)
+			if data != nil {
+				eventQueue.InsertClientProposal(node.ID, clientState.Id, clientState.LowWatermark, data, int64(runtimeParms.ProcessClientLatency))
+			}
+		}
+
+		r.schedule(node.ID, node)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/eventlog"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+var _ = Describe("Continuation", func() {
+	var (
+		recorded    *bytes.Buffer
+		recordedLen int
+	)
+
+	BeforeEach(func() {
+		recorder := (&testengine.Spec{
+			NodeCount:     4,
+			ClientCount:   2,
+			ReqsPerClient: 20,
+		}).Recorder()
+		recorder.LogOutput = ioutil.Discard
+
+		recorded = &bytes.Buffer{}
+		gzWriter := gzip.NewWriter(recorded)
+		recording, err := recorder.Recording(gzWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = recording.DrainClients(50000)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(gzWriter.Close()).To(Succeed())
+
+		reader, err := eventlog.NewReader(bytes.NewReader(recorded.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		recordedLen = 0
+		for _, err := reader.ReadEvent(); err != io.EOF; _, err = reader.ReadEvent() {
+			Expect(err).NotTo(HaveOccurred())
+			recordedLen++
+		}
+	})
+
+	// continueAt replays the recorded events before the given fraction of
+	// the recording, and continues from there.
+	continueAt := func(fraction float64) *testengine.Recording {
+		reader, err := eventlog.NewReader(bytes.NewReader(recorded.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+
+		continuation := testengine.NewContinuation(crypto.SHA256)
+		nodes := map[uint64]*testengine.ContinuedNode{}
+		for i := 0; i < int(fraction*float64(recordedLen)); i++ {
+			event, err := reader.ReadEvent()
+			Expect(err).NotTo(HaveOccurred())
+
+			if _, ok := event.StateEvent.Type.(*state.Event_Initialize); ok {
+				nodes[event.NodeId] = &testengine.ContinuedNode{
+					StateMachine: &statemachine.StateMachine{
+						Logger: testengine.NamedLogger{
+							Output: ioutil.Discard,
+							Level:  statemachine.LevelError,
+							Name:   fmt.Sprintf("node%d", event.NodeId),
+						},
+					},
+					PendingActions: &statemachine.ActionList{},
+				}
+			}
+			node := nodes[event.NodeId]
+
+			node.PendingActions.PushBackList(node.StateMachine.ApplyEvent(event.StateEvent))
+			actions := node.PendingActions
+			if _, ok := event.StateEvent.Type.(*state.Event_ActionsReceived); ok {
+				node.PendingActions = &statemachine.ActionList{}
+			}
+
+			Expect(continuation.Observe(event, actions)).To(Succeed())
+		}
+
+		recording, err := continuation.Recording(nodes, gzip.NewWriter(ioutil.Discard), ioutil.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		return recording
+	}
+
+	DescribeTable("continues the recording until the clients drain",
+		func(fraction float64) {
+			recording := continueAt(fraction)
+			Expect(recording.Nodes).To(HaveLen(4))
+			Expect(recording.Clients).To(HaveLen(2))
+
+			_, err := recording.DrainClients(50000)
+			Expect(err).NotTo(HaveOccurred())
+
+			activeHash := recording.Nodes[0].State.ActiveHash.Sum(nil)
+			for _, node := range recording.Nodes[1:] {
+				Expect(node.State.ActiveHash.Sum(nil)).To(Equal(activeHash))
+			}
+		},
+		Entry("shortly after initialization", 0.05),
+		Entry("halfway through", 0.5),
+		Entry("near the end", 0.9),
+	)
+
+	It("proposes the requests recorded", func() {
+		recording := continueAt(0.5)
+		for _, client := range recording.Clients {
+			Expect(client.Config.Total).To(Equal(uint64(20)))
+		}
+	})
+
+	It("rejects continuing before the nodes complete initialization", func() {
+		continuation := testengine.NewContinuation(crypto.SHA256)
+		reader, err := eventlog.NewReader(bytes.NewReader(recorded.Bytes()))
+		Expect(err).NotTo(HaveOccurred())
+		event, err := reader.ReadEvent()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(continuation.Observe(event, nil)).To(Succeed())
+
+		_, err = continuation.Recording(nil, gzip.NewWriter(ioutil.Discard), ioutil.Discard)
+		Expect(err).To(MatchError(fmt.Sprintf("node %d has not completed initialization", event.NodeId)))
+	})
+})
@@ -65,3 +65,49 @@ var _ = Describe("Matchers", func() {
 		})
 	})
 })
+
+var _ = Describe("Scenario mangler", func() {
+	// dropped returns the random values for which the mangler drops a
+	// message.
+	dropped := func(mangler Mangler) []int {
+		var result []int
+		for random := 0; random < 200; random++ {
+			event := &Event{
+				Target: 1,
+				MsgReceived: &EventMsgReceived{
+					Source: 0,
+					Msg: &msgs.Msg{
+						Type: &msgs.Msg_Commit{
+							Commit: &msgs.Commit{},
+						},
+					},
+				},
+			}
+			if len(mangler.Mangle(random, event)) == 0 {
+				result = append(result, random)
+			}
+		}
+		return result
+	}
+
+	It("mangles the same events with a rule once the other rules are removed", func() {
+		scenario := &Scenario{
+			Manglers: []func() Mangler{
+				func() Mangler {
+					return For(MatchMsgs().FromNode(3)).Drop()
+				},
+				func() Mangler {
+					return For(MatchMsgs().AtPercent(50)).Drop()
+				},
+			},
+		}
+		composed := dropped(scenario.mangler())
+		Expect(composed).NotTo(BeEmpty())
+		Expect(len(composed)).To(BeNumerically("<", 200))
+
+		reduced := scenario.clone()
+		reduced.Manglers = reduced.Manglers[1:]
+		reduced.rules = []int{1}
+		Expect(dropped(reduced.mangler())).To(Equal(composed))
+	})
+})
@@ -0,0 +1,667 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+)
+
+// MessageFault drops or delays a single delivery of a message, identified
+// by its source, target, contents, and the number of identical deliveries
+// which preceded it.
+type MessageFault struct {
+	Source     uint64
+	Target     uint64
+	Msg        *msgs.Msg
+	Occurrence int
+	Drop       bool
+	Delay      int64
+}
+
+func (mf *MessageFault) String() string {
+	action := "drop"
+	if !mf.Drop {
+		action = fmt.Sprintf("delay %d", mf.Delay)
+	}
+	return fmt.Sprintf("%s %T from %d to %d (occurrence %d)", action, mf.Msg.Type, mf.Source, mf.Target, mf.Occurrence)
+}
+
+type faultKey struct {
+	source uint64
+	target uint64
+	msg    string
+}
+
+func newFaultKey(event *Event) faultKey {
+	msgBytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(event.MsgReceived.Msg)
+	if err != nil {
+		panic(fmt.Sprintf("could not marshal message: %s", err))
+	}
+
+	return faultKey{
+		source: event.MsgReceived.Source,
+		target: event.Target,
+		msg:    string(msgBytes),
+	}
+}
+
+type occurrence struct {
+	key   faultKey
+	count int
+}
+
+// occurrences counts the deliveries of each message.  Events which are
+// remangled are only counted the first time they are mangled.
+type occurrences struct {
+	counts  map[faultKey]int
+	counted map[*Event]occurrence
+}
+
+func newOccurrences() *occurrences {
+	return &occurrences{
+		counts:  map[faultKey]int{},
+		counted: map[*Event]occurrence{},
+	}
+}
+
+func (o *occurrences) of(event *Event) occurrence {
+	if occ, ok := o.counted[event]; ok {
+		return occ
+	}
+
+	key := newFaultKey(event)
+	occ := occurrence{key: key, count: o.counts[key]}
+	o.counts[key]++
+	o.counted[event] = occ
+	return occ
+}
+
+// faultMangler applies a list of message faults.
+type faultMangler struct {
+	faults      map[occurrence]*MessageFault
+	occurrences *occurrences
+}
+
+func newFaultMangler(faults []*MessageFault) *faultMangler {
+	fm := &faultMangler{
+		faults:      map[occurrence]*MessageFault{},
+		occurrences: newOccurrences(),
+	}
+
+	for _, fault := range faults {
+		fm.faults[occurrence{
+			key: newFaultKey(&Event{
+				Target: fault.Target,
+				MsgReceived: &EventMsgReceived{
+					Source: fault.Source,
+					Msg:    fault.Msg,
+				},
+			}),
+			count: fault.Occurrence,
+		}] = fault
+	}
+
+	return fm
+}
+
+func (fm *faultMangler) Mangle(random int, event *Event) []MangleResult {
+	if event.MsgReceived == nil {
+		return []MangleResult{{Event: event}}
+	}
+
+	fault, ok := fm.faults[fm.occurrences.of(event)]
+	if !ok {
+		return []MangleResult{{Event: event}}
+	}
+
+	delete(fm.faults, fm.occurrences.of(event))
+	if fault.Drop {
+		return nil
+	}
+
+	event.Time += fault.Delay
+	return []MangleResult{{Event: event}}
+}
+
+// faultRecorder wraps a mangler, recording the messages it drops or
+// delays as message faults.  Duplicated messages and other mangled events
+// have no message fault equivalent and are not recorded.
+type faultRecorder struct {
+	mangler     Mangler
+	occurrences *occurrences
+	times       map[*Event]int64
+	faults      []*MessageFault
+}
+
+func (fr *faultRecorder) Mangle(random int, event *Event) []MangleResult {
+	if event.MsgReceived == nil {
+		return fr.mangler.Mangle(random, event)
+	}
+
+	occ := fr.occurrences.of(event)
+	originalTime, ok := fr.times[event]
+	if !ok {
+		originalTime = event.Time
+	}
+
+	fault := &MessageFault{
+		Source:     event.MsgReceived.Source,
+		Target:     event.Target,
+		Msg:        event.MsgReceived.Msg,
+		Occurrence: occ.count,
+		Drop:       true,
+	}
+
+	results := fr.mangler.Mangle(random, event)
+	for _, result := range results {
+		if result.Event != event {
+			continue
+		}
+
+		fault.Drop = false
+		if result.Remangle {
+			fr.times[event] = originalTime
+			return results
+		}
+		fault.Delay = event.Time - originalTime
+	}
+
+	delete(fr.times, event)
+	if fault.Drop || fault.Delay != 0 {
+		fr.faults = append(fr.faults, fault)
+	}
+
+	return results
+}
+
+// composedMangler applies each of its manglers in turn to the results of
+// the previous ones.
+type composedMangler struct {
+	manglers []Mangler
+
+	// keys identify each mangler, independent of the others composed.
+	keys []uint64
+}
+
+func (cm *composedMangler) add(key uint64, mangler Mangler) {
+	cm.manglers = append(cm.manglers, mangler)
+	cm.keys = append(cm.keys, key)
+}
+
+func (cm *composedMangler) Mangle(random int, event *Event) []MangleResult {
+	results := []MangleResult{{Event: event}}
+	for i, mangler := range cm.manglers {
+		// Derive an independent random value for each mangler, so that,
+		// for instance, the AtPercent filters of different rules do not
+		// match the same events.  It is derived from the mangler's key
+		// rather than its position, so that removing other rules does
+		// not change the events a rule mangles.
+		ruleRandom := int(mix(uint64(random)+cm.keys[i]) >> 1)
+
+		var next []MangleResult
+		for _, result := range results {
+			for _, mangled := range mangler.Mangle(ruleRandom, result.Event) {
+				mangled.Remangle = mangled.Remangle || result.Remangle
+				next = append(next, mangled)
+			}
+		}
+		results = next
+	}
+	return results
+}
+
+// mix is the splitmix64 finalizer.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// Scenario is a testengine run which may be minimized.  The run executes
+// until all client requests commit, or until it fails.
+type Scenario struct {
+	Spec       *Spec
+	RandomSeed int64
+
+	// Manglers construct the mangling rules, applied in order.  Rules are
+	// constructed afresh for each run, as manglers such as those built by
+	// After and Until are stateful.
+	Manglers []func() Mangler
+
+	// Faults are message faults applied before the mangling rules.
+	Faults []*MessageFault
+
+	// MaxSteps is the number of steps after which the run fails with a
+	// timeout.
+	MaxSteps int
+
+	// Check, if set, is invoked after each step, and fails the run if
+	// it returns an error.
+	Check func(*Recording) error
+
+	// rules holds the original position of each of Manglers, once the
+	// minimizer has removed some.
+	rules []int
+}
+
+// PanicError is the failure of a run which panicked.
+type PanicError struct {
+	Value interface{}
+}
+
+func (pe *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", pe.Value)
+}
+
+// TimeoutError is the failure of a run which did not commit all client
+// requests within its maximum number of steps.
+type TimeoutError struct {
+	Steps int
+}
+
+func (te *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out after %d steps", te.Steps)
+}
+
+func (s *Scenario) clone() *Scenario {
+	clone := *s
+	clone.Manglers = append([]func() Mangler(nil), s.Manglers...)
+	clone.Faults = append([]*MessageFault(nil), s.Faults...)
+	clone.rules = append([]int(nil), s.rules...)
+	return &clone
+}
+
+// mangler composes the faults and rules of the scenario.  Even a single rule
+// is composed, so that it mangles the same events as it did alongside the
+// rules the minimizer removed.
+func (s *Scenario) mangler() Mangler {
+	if len(s.Faults) == 0 && len(s.Manglers) == 0 {
+		return nil
+	}
+
+	manglers := &composedMangler{}
+	if len(s.Faults) > 0 {
+		manglers.add(0, newFaultMangler(s.Faults))
+	}
+	for i, newMangler := range s.Manglers {
+		rule := i
+		if s.rules != nil {
+			rule = s.rules[i]
+		}
+		manglers.add(uint64(rule)+1, newMangler())
+	}
+
+	return manglers
+}
+
+// Run executes the scenario, writing its recording to output, and its
+// logs to logOutput.  It returns the number of steps executed, and the
+// failure of the run, if any.  An error is returned only if the run could
+// not be started.
+func (s *Scenario) Run(output *gzip.Writer, logOutput io.Writer) (steps int, failure error, err error) {
+	if s.MaxSteps <= 0 {
+		return 0, nil, errors.Errorf("scenario must set MaxSteps")
+	}
+
+	return s.run(output, logOutput, s.mangler())
+}
+
+func (s *Scenario) run(output *gzip.Writer, logOutput io.Writer, mangler Mangler) (steps int, failure error, err error) {
+
+	recorder := s.Spec.Recorder()
+	recorder.LogOutput = logOutput
+	recorder.RandomSeed = s.RandomSeed
+	recorder.Mangler = mangler
+
+	recording, err := recorder.Recording(output)
+	if err != nil {
+		return 0, nil, errors.WithMessage(err, "could not create recording")
+	}
+
+	targetReqs := map[uint64]uint64{}
+	for _, client := range recording.Clients {
+		targetReqs[client.Config.ID] = client.Config.Total
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			failure = &PanicError{Value: r}
+		}
+	}()
+
+	for {
+		steps++
+		if err := recording.Step(); err != nil {
+			return steps, err, nil
+		}
+
+		if s.Check != nil {
+			if err := s.Check(recording); err != nil {
+				return steps, err, nil
+			}
+		}
+
+		if recording.clientsDrained(targetReqs) {
+			return steps, nil, nil
+		}
+
+		if steps >= s.MaxSteps {
+			return steps, &TimeoutError{Steps: steps}, nil
+		}
+	}
+}
+
+var digits = regexp.MustCompile(`[0-9]+`)
+
+// SameFailure returns whether the failures are of the same type, with the
+// same message, ignoring any numbers within it.
+func SameFailure(original, candidate error) bool {
+	if original == nil || candidate == nil {
+		return original == candidate
+	}
+
+	return fmt.Sprintf("%T", original) == fmt.Sprintf("%T", candidate) &&
+		digits.ReplaceAllString(original.Error(), "N") == digits.ReplaceAllString(candidate.Error(), "N")
+}
+
+// Minimizer shrinks a failing scenario to one with fewer steps, mangling
+// rules, and message faults which still reproduces the failure.
+type Minimizer struct {
+	Scenario *Scenario
+
+	// Seeds is the number of alternative random seeds to try.
+	Seeds int
+
+	// MaxRuns, if non-zero, bounds the number of runs performed.
+	MaxRuns int
+
+	// Reproduces returns whether the failure of a candidate run is the
+	// original failure, it defaults to SameFailure.
+	Reproduces func(original, candidate error) bool
+
+	// Log, if set, receives a line for each improvement.
+	Log io.Writer
+
+	original    error
+	best        *Scenario
+	bestFailure error
+	bestSteps   int
+	runs        int
+}
+
+// MinimizedScenario is the result of minimization.
+type MinimizedScenario struct {
+	Scenario *Scenario
+	Steps    int
+	Failure  error
+	Runs     int
+}
+
+func (m *Minimizer) logf(format string, args ...interface{}) {
+	if m.Log != nil {
+		fmt.Fprintf(m.Log, "minimizer: "+format+"\n", args...)
+	}
+}
+
+func (m *Minimizer) exhausted() bool {
+	return m.MaxRuns != 0 && m.runs >= m.MaxRuns
+}
+
+func (m *Minimizer) run(scenario *Scenario, mangler Mangler) (int, error, error) {
+	m.runs++
+	gzWriter, err := gzip.NewWriterLevel(ioutil.Discard, gzip.NoCompression)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer gzWriter.Close()
+
+	return scenario.run(gzWriter, ioutil.Discard, mangler)
+}
+
+// try runs the candidate, adopting it if it reproduces the failure in no
+// more steps than the best scenario so far.
+func (m *Minimizer) try(candidate *Scenario, description string) (bool, error) {
+	if m.exhausted() {
+		return false, nil
+	}
+
+	steps, failure, err := m.run(candidate, candidate.mangler())
+	if err != nil {
+		return false, err
+	}
+
+	if failure == nil || !m.Reproduces(m.original, failure) || steps > m.bestSteps {
+		return false, nil
+	}
+
+	m.best, m.bestFailure, m.bestSteps = candidate, failure, steps
+	m.logf("%s reproduces in %d steps with %d rules and %d faults", description, steps, len(candidate.Manglers), len(candidate.Faults))
+	return true, nil
+}
+
+// ddmin is the delta debugging minimization algorithm.  It returns a
+// minimal subset of the indices [0, n) for which test succeeds, assuming
+// it succeeds for all of them.
+func ddmin(n int, test func(indices []int) (bool, error)) ([]int, error) {
+	indices := make([]int, n)
+	for i := range indices {
+		indices[i] = i
+	}
+
+	if n == 0 {
+		return indices, nil
+	}
+
+	if ok, err := test(nil); err != nil || ok {
+		return nil, err
+	}
+
+	granularity := 2
+	for len(indices) >= 2 {
+		if granularity > len(indices) {
+			granularity = len(indices)
+		}
+
+		chunks := make([][]int, granularity)
+		for i, index := range indices {
+			chunk := i * granularity / len(indices)
+			chunks[chunk] = append(chunks[chunk], index)
+		}
+
+		reduced := false
+		for _, chunk := range chunks {
+			ok, err := test(chunk)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				indices, granularity, reduced = chunk, 2, true
+				break
+			}
+		}
+
+		if !reduced && granularity > 2 {
+			for i := range chunks {
+				var complement []int
+				for j, chunk := range chunks {
+					if j != i {
+						complement = append(complement, chunk...)
+					}
+				}
+
+				ok, err := test(complement)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					indices, granularity, reduced = complement, granularity-1, true
+					break
+				}
+			}
+		}
+
+		if reduced {
+			continue
+		}
+
+		if granularity == len(indices) {
+			break
+		}
+		granularity *= 2
+	}
+
+	return indices, nil
+}
+
+func (m *Minimizer) minimizeManglers() error {
+	manglers := m.best.Manglers
+	_, err := ddmin(len(manglers), func(indices []int) (bool, error) {
+		candidate := m.best.clone()
+		candidate.Manglers = nil
+		candidate.rules = []int{}
+		for _, i := range indices {
+			candidate.Manglers = append(candidate.Manglers, manglers[i])
+			rule := i
+			if m.best.rules != nil {
+				rule = m.best.rules[i]
+			}
+			candidate.rules = append(candidate.rules, rule)
+		}
+		return m.try(candidate, fmt.Sprintf("keeping rules %v", indices))
+	})
+	return err
+}
+
+func (m *Minimizer) minimizeFaults() error {
+	faults := m.best.Faults
+	_, err := ddmin(len(faults), func(indices []int) (bool, error) {
+		candidate := m.best.clone()
+		candidate.Faults = nil
+		for _, i := range indices {
+			candidate.Faults = append(candidate.Faults, faults[i])
+		}
+		return m.try(candidate, fmt.Sprintf("keeping %d faults", len(indices)))
+	})
+	return err
+}
+
+// shortenDelays halves each remaining delay for as long as the failure
+// still reproduces.
+func (m *Minimizer) shortenDelays() error {
+	for i := range m.best.Faults {
+		for {
+			fault := m.best.Faults[i]
+			if fault.Drop || fault.Delay <= 1 {
+				break
+			}
+
+			shorter := *fault
+			shorter.Delay /= 2
+
+			candidate := m.best.clone()
+			candidate.Faults[i] = &shorter
+			ok, err := m.try(candidate, fmt.Sprintf("delaying by %d", shorter.Delay))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// Minimize runs the scenario, which must fail, and then searches for a
+// smaller scenario reproducing the failure.  It tries alternative random
+// seeds, removes mangling rules, replaces the remaining rules with the
+// message drops and delays they caused, and finally removes those faults
+// and shortens the delays.
+func (m *Minimizer) Minimize() (*MinimizedScenario, error) {
+	if m.Reproduces == nil {
+		m.Reproduces = SameFailure
+	}
+
+	if m.Scenario.MaxSteps <= 0 {
+		return nil, errors.Errorf("scenario must set MaxSteps")
+	}
+
+	m.best = m.Scenario.clone()
+	steps, failure, err := m.run(m.best, m.best.mangler())
+	if err != nil {
+		return nil, err
+	}
+	if failure == nil {
+		return nil, errors.Errorf("scenario did not fail after %d steps", steps)
+	}
+	m.original, m.bestFailure, m.bestSteps = failure, failure, steps
+	m.logf("original scenario fails in %d steps with: %s", steps, failure)
+
+	if len(m.best.Manglers) > 0 {
+		seed := m.best.RandomSeed
+		for i := 1; i <= m.Seeds; i++ {
+			candidate := m.best.clone()
+			candidate.RandomSeed = seed + int64(i)
+			if _, err := m.try(candidate, fmt.Sprintf("seed %d", candidate.RandomSeed)); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := m.minimizeManglers(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(m.best.Manglers) > 0 && !m.exhausted() {
+		recorder := &faultRecorder{
+			mangler:     m.best.mangler(),
+			occurrences: newOccurrences(),
+			times:       map[*Event]int64{},
+		}
+		if _, _, err := m.run(m.best, recorder); err != nil {
+			return nil, err
+		}
+
+		candidate := m.best.clone()
+		candidate.Manglers = nil
+		candidate.rules = nil
+		candidate.Faults = recorder.faults
+		if _, err := m.try(candidate, fmt.Sprintf("replacing rules with %d faults", len(recorder.faults))); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(m.best.Faults) > 0 {
+		if err := m.minimizeFaults(); err != nil {
+			return nil, err
+		}
+
+		if err := m.shortenDelays(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &MinimizedScenario{
+		Scenario: m.best,
+		Steps:    m.bestSteps,
+		Failure:  m.bestFailure,
+		Runs:     m.runs,
+	}, nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testengine_test
+
+import (
+	"compress/gzip"
+	"io/ioutil"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/testengine"
+)
+
+var _ = Describe("Minimizer", func() {
+	var (
+		scenario *testengine.Scenario
+	)
+
+	run := func(scenario *testengine.Scenario) (int, error) {
+		gzWriter := gzip.NewWriter(ioutil.Discard)
+		defer gzWriter.Close()
+
+		steps, failure, err := scenario.Run(gzWriter, ioutil.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		return steps, failure
+	}
+
+	BeforeEach(func() {
+		scenario = &testengine.Scenario{
+			Spec: &testengine.Spec{
+				NodeCount:     4,
+				ClientCount:   1,
+				ReqsPerClient: 5,
+			},
+			Manglers: []func() testengine.Mangler{
+				func() testengine.Mangler {
+					return testengine.For(testengine.MatchMsgs().OfTypeRequestAck().AtPercent(10)).Jitter(30)
+				},
+				func() testengine.Mangler {
+					return testengine.For(testengine.MatchMsgs().FromNode(0).OfTypePreprepare()).Drop()
+				},
+				func() testengine.Mangler {
+					return testengine.For(testengine.MatchMsgs().ToNode(2).OfTypeCheckpoint()).Delay(20)
+				},
+			},
+			MaxSteps: 20000,
+			Check: func(r *testengine.Recording) error {
+				for _, node := range r.Nodes {
+					if node.StateMachine == nil {
+						continue
+					}
+					if epoch := node.StateMachine.Facets().EpochNumber; epoch >= 2 {
+						return errors.Errorf("node %d entered epoch %d", node.Config.InitParms.Id, epoch)
+					}
+				}
+				return nil
+			},
+		}
+	})
+
+	It("reduces the mangling rules to the message faults causing the failure", func() {
+		originalSteps, originalFailure := run(scenario)
+		Expect(originalFailure).To(MatchError(MatchRegexp(`node \d entered epoch 2`)))
+
+		result, err := (&testengine.Minimizer{
+			Scenario: scenario,
+			Seeds:    2,
+		}).Minimize()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Steps).To(BeNumerically("<", originalSteps))
+		Expect(result.Failure).To(MatchError(MatchRegexp(`node \d entered epoch 2`)))
+		Expect(result.Scenario.Manglers).To(BeEmpty())
+		Expect(result.Scenario.Faults).NotTo(BeEmpty())
+		for _, fault := range result.Scenario.Faults {
+			Expect(fault.Drop).To(BeTrue())
+			Expect(fault.Source).To(Equal(uint64(0)))
+			Expect(fault.Msg.Type).To(BeAssignableToTypeOf(&msgs.Msg_Preprepare{}))
+		}
+
+		steps, failure := run(result.Scenario)
+		Expect(steps).To(Equal(result.Steps))
+		Expect(testengine.SameFailure(originalFailure, failure)).To(BeTrue())
+	})
+
+	It("shortens message delays", func() {
+		scenario.Manglers = []func() testengine.Mangler{
+			func() testengine.Mangler {
+				return testengine.For(testengine.MatchMsgs().FromNode(0).OfTypePreprepare()).Jitter(20000)
+			},
+		}
+
+		result, err := (&testengine.Minimizer{
+			Scenario: scenario,
+		}).Minimize()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Scenario.Manglers).To(BeEmpty())
+		Expect(result.Scenario.Faults).NotTo(BeEmpty())
+		for _, fault := range result.Scenario.Faults {
+			Expect(fault.Drop).To(BeFalse())
+			Expect(fault.Delay).To(BeNumerically(">", 0))
+		}
+
+		_, failure := run(result.Scenario)
+		Expect(failure).To(MatchError(result.Failure.Error()))
+	})
+
+	It("bounds the number of runs", func() {
+		result, err := (&testengine.Minimizer{
+			Scenario: scenario,
+			Seeds:    5,
+			MaxRuns:  3,
+		}).Minimize()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Runs).To(Equal(3))
+		Expect(result.Scenario.Manglers).To(HaveLen(3))
+	})
+
+	When("the scenario does not fail", func() {
+		It("returns an error", func() {
+			scenario.Manglers = nil
+			_, err := (&testengine.Minimizer{
+				Scenario: scenario,
+			}).Minimize()
+			Expect(err).To(MatchError(MatchRegexp(`scenario did not fail after \d+ steps`)))
+		})
+	})
+})
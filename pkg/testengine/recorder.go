@@ -62,6 +62,34 @@ type RuntimeParameters struct {
 	ProcessAppLatency      int
 	ProcessReqStoreLatency int
 	ProcessEventsLatency   int
+
+	// ClientRetryLatency is the delay before a client retries a proposal
+	// its node could not accept, as the node had not yet learned of the
+	// client, or applied backpressure.  When zero, it defaults to a hundred
+	// times ProcessClientLatency.
+	ClientRetryLatency int
+}
+
+func (rp *RuntimeParameters) clientRetryLatency() int64 {
+	if rp.ClientRetryLatency == 0 {
+		return int64(rp.ProcessClientLatency * 100)
+	}
+	return int64(rp.ClientRetryLatency)
+}
+
+func defaultRuntimeParameters() *RuntimeParameters {
+	return &RuntimeParameters{
+		TickInterval:           500,
+		LinkLatency:            100,
+		ProcessWALLatency:      100,
+		ProcessNetLatency:      15,
+		ProcessHashLatency:     25,
+		ProcessClientLatency:   15,
+		ProcessAppLatency:      30,
+		ProcessReqStoreLatency: 150,
+		ProcessEventsLatency:   10,
+		ClientRetryLatency:     1500,
+	}
 }
 
 type clientReq struct {
@@ -261,8 +289,13 @@ func (rc *RecorderClient) RequestByReqNo(reqNo uint64) []byte {
 		return nil
 	}
 
+	return ClientRequestData(rc.Config.ID, reqNo)
+}
+
+// ClientRequestData returns the data recorder clients propose for a request.
+func ClientRequestData(clientID, reqNo uint64) []byte {
 	var buf bytes.Buffer
-	buf.Write(uint64ToBytes(rc.Config.ID))
+	buf.Write(uint64ToBytes(clientID))
 	buf.Write([]byte("-"))
 	buf.Write(uint64ToBytes(reqNo))
 
@@ -394,12 +427,39 @@ type Recorder struct {
 	LogOutput      io.Writer
 	Hasher         processor.Hasher
 	RandomSeed     int64
+
+	// RecordActions records the actions produced by the state machine along
+	// with each ActionsReceived event, see eventlog.RecordActionsOpt.
+	RecordActions bool
 }
 
-type interceptorFunc func(*state.Event) error
+// recordingInterceptor writes each intercepted event of a node to the
+// recording output, along with the produced actions if configured.
+type recordingInterceptor struct {
+	nodeID        uint64
+	output        io.Writer
+	eventQueue    *EventQueue
+	recordActions bool
+}
 
-func (icf interceptorFunc) Intercept(e *state.Event) error {
-	return icf(e)
+func (ri *recordingInterceptor) Intercept(e *state.Event) error {
+	return ri.write(e, nil)
+}
+
+func (ri *recordingInterceptor) InterceptActions(e *state.Event, actions *statemachine.ActionList) error {
+	if !ri.recordActions {
+		actions = nil
+	}
+	return ri.write(e, actions)
+}
+
+func (ri *recordingInterceptor) write(e *state.Event, actions *statemachine.ActionList) error {
+	return eventlog.WriteRecordedEvent(ri.output, &recording.Event{
+		NodeId:     ri.nodeID,
+		Time:       ri.eventQueue.FakeTime,
+		StateEvent: e,
+		Actions:    eventlog.RecordedActions(actions),
+	})
 }
 
 func (r *Recorder) Recording(output *gzip.Writer) (*Recording, error) {
@@ -439,13 +499,12 @@ func (r *Recorder) Recording(output *gzip.Writer) (*Recording, error) {
 				Source:     nodeID,
 				Delay:      int64(recorderNodeConfig.RuntimeParms.LinkLatency),
 			},
-			Interceptor: interceptorFunc(func(e *state.Event) error {
-				return eventlog.WriteRecordedEvent(output, &recording.Event{
-					NodeId:     nodeID,
-					Time:       eventQueue.FakeTime,
-					StateEvent: e,
-				})
-			}),
+			Interceptor: &recordingInterceptor{
+				nodeID:        nodeID,
+				output:        output,
+				eventQueue:    eventQueue,
+				recordActions: r.RecordActions,
+			},
 			Config: recorderNodeConfig,
 		}
 
@@ -543,7 +602,7 @@ func (r *Recording) Step() error {
 		client := node.Clients.Client(prop.ClientID)
 		reqNo, err := client.NextReqNo()
 		if errors.Is(err, processor.ErrClientNotExist) {
-			r.EventQueue.InsertClientProposal(nodeID, prop.ClientID, prop.ReqNo, prop.Data, int64(runtimeParms.ProcessClientLatency*100))
+			r.EventQueue.InsertClientProposal(nodeID, prop.ClientID, prop.ReqNo, prop.Data, runtimeParms.clientRetryLatency())
 			break
 		}
 
@@ -565,6 +624,11 @@ func (r *Recording) Step() error {
 		}
 
 		events, err := client.Propose(prop.ReqNo, prop.Data)
+		if errors.Is(err, processor.ErrBackpressure) {
+			r.EventQueue.InsertClientProposal(nodeID, prop.ClientID, prop.ReqNo, prop.Data, runtimeParms.clientRetryLatency())
+			break
+		}
+
 		if err != nil {
 			return errors.WithMessage(err, "unanticipated client propose error")
 		}
@@ -581,7 +645,7 @@ func (r *Recording) Step() error {
 		node.WorkItems.AddReqStoreResults(event.ProcessReqStoreEvents)
 		node.ProcessReqStoreEventsPending = false
 	case event.ProcessResultEvents != nil:
-		actions, err := processor.ProcessStateMachineEvents(node.StateMachine, node.Interceptor, event.ProcessResultEvents)
+		actions, err := processor.ProcessStateMachineEvents(node.StateMachine, node.Interceptor, node.ReqStore, event.ProcessResultEvents)
 		if err != nil {
 			return errors.WithMessage(err, "could not process state machine events")
 		}
@@ -626,10 +690,20 @@ func (r *Recording) Step() error {
 		return errors.Errorf("unknown event type")
 	}
 
+	r.schedule(nodeID, node)
+
+	return nil
+}
+
+// schedule enqueues the processing of the node's outstanding work items,
+// for each kind of work not already pending.
+func (r *Recording) schedule(nodeID uint64, node *Node) {
 	if node.WorkItems == nil {
-		return nil
+		return
 	}
 
+	runtimeParms := node.Config.RuntimeParms
+
 	if !node.ProcessWALActionsPending && node.WorkItems.WALActions().Len() > 0 {
 		node.ProcessWALActionsPending = true
 		r.EventQueue.InsertProcessWALActions(nodeID, node.WorkItems.WALActions(), int64(runtimeParms.ProcessWALLatency))
@@ -672,19 +746,13 @@ func (r *Recording) Step() error {
 		r.EventQueue.InsertProcessResultEvents(nodeID, events, int64(runtimeParms.ProcessEventsLatency))
 		node.WorkItems.ClearResultEvents()
 	}
-
-	return nil
 }
 
 // DrainClients will execute the recording until all client requests have committed.
 // It will return with an error if the number of accumulated log entries exceeds timeout.
 // If any step returns an error, this function returns that error.
 func (r *Recording) DrainClients(timeout int) (count int, err error) {
-
-	targetReqs := map[uint64]uint64{}
-	for _, client := range r.Clients {
-		targetReqs[client.Config.ID] = client.Config.Total
-	}
+	targetReqs := r.clientTargets()
 
 	for {
 		count++
@@ -693,18 +761,7 @@ func (r *Recording) DrainClients(timeout int) (count int, err error) {
 			return 0, err
 		}
 
-		allDone := true
-	outer:
-		for _, node := range r.Nodes {
-			for _, client := range node.State.CheckpointState.Clients {
-				if targetReqs[client.Id] != client.LowWatermark {
-					allDone = false
-					break outer
-				}
-			}
-		}
-
-		if allDone {
+		if r.clientsDrained(targetReqs) {
 			return count, nil
 		}
 
@@ -722,6 +779,34 @@ func (r *Recording) DrainClients(timeout int) (count int, err error) {
 	}
 }
 
+// ClientsDrained returns whether every node has committed every request
+// of every client.
+func (r *Recording) ClientsDrained() bool {
+	return r.clientsDrained(r.clientTargets())
+}
+
+func (r *Recording) clientTargets() map[uint64]uint64 {
+	targetReqs := map[uint64]uint64{}
+	for _, client := range r.Clients {
+		targetReqs[client.Config.ID] = client.Config.Total
+	}
+	return targetReqs
+}
+
+// clientsDrained returns whether every node has committed every request
+// of every client, according to targetReqs.
+func (r *Recording) clientsDrained(targetReqs map[uint64]uint64) bool {
+	for _, node := range r.Nodes {
+		for _, client := range node.State.CheckpointState.Clients {
+			if targetReqs[client.Id] != client.LowWatermark {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 type Spec struct {
 	NodeCount     int
 	ClientCount   int
@@ -748,17 +833,7 @@ func (s *Spec) Recorder() *Recorder {
 				BufferSize:           5 * 1024 * 1024,
 				BatchSize:            batchSize,
 			},
-			RuntimeParms: &RuntimeParameters{
-				TickInterval:           500,
-				LinkLatency:            100,
-				ProcessWALLatency:      100,
-				ProcessNetLatency:      15,
-				ProcessHashLatency:     25,
-				ProcessClientLatency:   15,
-				ProcessAppLatency:      30,
-				ProcessReqStoreLatency: 150,
-				ProcessEventsLatency:   10,
-			},
+			RuntimeParms: defaultRuntimeParameters(),
 		})
 	}
 
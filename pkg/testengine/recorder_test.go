@@ -83,7 +83,7 @@ var _ = Describe("Recorder", func() {
 		It("Executes and produces a log", func() {
 			count, err := recording.DrainClients(50000)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(count).To(Equal(43950))
+			Expect(count).To(Equal(44794))
 
 			fmt.Printf("Executing test required a log of %d events\n", count)
 
@@ -95,7 +95,7 @@ var _ = Describe("Recorder", func() {
 				//Expect(status.EpochTracker.EpochTargets[0].Suspicions).To(BeEmpty())
 
 				// Expect(fmt.Sprintf("%x", node.State.ActiveHash.Sum(nil))).To(BeEmpty())
-				Expect(fmt.Sprintf("%x", node.State.ActiveHash.Sum(nil))).To(Equal("cb81c7299ad4019baca241f267d570f1b451b751717ce18bb8efc16ae8a555c4"))
+				Expect(fmt.Sprintf("%x", node.State.ActiveHash.Sum(nil))).To(Equal("c18145c5cf9d82ce4da400cdc8f9a177a1ed9a4fe32c7fbaeb1e63f7a9a9d239"))
 			}
 		})
 	})
@@ -119,4 +119,28 @@ var _ = Describe("Recorder", func() {
 			Expect(count).To(Equal(67))
 		})
 	})
+
+	When("The client retry latency is unset", func() {
+		BeforeEach(func() {
+			recorder = (&testengine.Spec{
+				NodeCount:     4,
+				ClientCount:   4,
+				ReqsPerClient: 200,
+			}).Recorder()
+
+			for _, nodeConfig := range recorder.NodeConfigs {
+				nodeConfig.RuntimeParms.ClientRetryLatency = 0
+			}
+
+			var err error
+			recording, err = recorder.Recording(gzWriter)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("retries proposals as it would by default", func() {
+			count, err := recording.DrainClients(50000)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(count).To(Equal(44794))
+		})
+	})
 })
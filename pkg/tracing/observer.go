@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/state"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+)
+
+// Observer derives the spans of a single node from the events applied to its
+// state machine and the actions the state machine returns.  It implements
+// statemachine.RequestTracer for the transitions which produce no action.
+// It is safe for concurrent use.
+type Observer struct {
+	nodeID uint64
+	tracer Tracer
+	now    func() time.Time
+
+	mutex sync.Mutex
+
+	// batches holds the requests of each preprepared sequence until it commits.
+	batches map[uint64][]*msgs.RequestAck
+
+	// committed holds the committed batches awaiting each checkpoint.
+	committed map[uint64][]*state.ActionCommit
+}
+
+// NewObserver creates an observer emitting the spans of the given node to tracer.
+func NewObserver(nodeID uint64, tracer Tracer) *Observer {
+	return &Observer{
+		nodeID:    nodeID,
+		tracer:    tracer,
+		now:       time.Now,
+		batches:   map[uint64][]*msgs.RequestAck{},
+		committed: map[uint64][]*state.ActionCommit{},
+	}
+}
+
+func (o *Observer) trace(stage Stage, ack *msgs.RequestAck, seqNo uint64) {
+	o.tracer.Trace(&Span{
+		NodeID:   o.nodeID,
+		ClientID: ack.ClientId,
+		ReqNo:    ack.ReqNo,
+		Digest:   ack.Digest,
+		Stage:    stage,
+		SeqNo:    seqNo,
+		Time:     o.now(),
+	})
+}
+
+// Proposed emits a proposed span for each request persisted by the events
+// resulting from a local proposal.
+func (o *Observer) Proposed(events *statemachine.EventList) {
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if rp, ok := event.Type.(*state.Event_RequestPersisted); ok {
+			o.trace(StageProposed, rp.RequestPersisted.RequestAck, 0)
+		}
+	}
+}
+
+// Events emits spans for the events about to be applied to the state machine.
+func (o *Observer) Events(events *statemachine.EventList) {
+	iter := events.Iterator()
+	for event := iter.Next(); event != nil; event = iter.Next() {
+		if rp, ok := event.Type.(*state.Event_RequestPersisted); ok {
+			o.trace(StagePersisted, rp.RequestPersisted.RequestAck, 0)
+		}
+	}
+}
+
+// Actions emits spans for the actions returned by the state machine.
+func (o *Observer) Actions(actions *statemachine.ActionList) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		switch t := action.Type.(type) {
+		case *state.Action_Send:
+			if ra, ok := t.Send.Msg.Type.(*msgs.Msg_RequestAck); ok {
+				o.trace(StageAcked, ra.RequestAck, 0)
+			}
+		case *state.Action_AppendWriteAhead:
+			o.persisted(t.AppendWriteAhead.Data)
+		case *state.Action_Commit:
+			seqNo := t.Commit.Batch.SeqNo
+			for _, ack := range t.Commit.Batch.Requests {
+				o.trace(StageCommitted, ack, seqNo)
+			}
+			delete(o.batches, seqNo)
+			o.committed[t.Commit.CheckpointSeqNo] = append(o.committed[t.Commit.CheckpointSeqNo], t.Commit)
+		}
+	}
+}
+
+func (o *Observer) persisted(entry *msgs.Persistent) {
+	switch t := entry.Type.(type) {
+	case *msgs.Persistent_QEntry:
+		seqNo := t.QEntry.SeqNo
+		for _, ack := range t.QEntry.Requests {
+			o.trace(StagePreprepared, ack, seqNo)
+		}
+		o.batches[seqNo] = t.QEntry.Requests
+	case *msgs.Persistent_PEntry:
+		seqNo := t.PEntry.SeqNo
+		for _, ack := range o.batches[seqNo] {
+			o.trace(StagePrepared, ack, seqNo)
+		}
+	case *msgs.Persistent_CEntry:
+		for _, commit := range o.committed[t.CEntry.SeqNo] {
+			for _, ack := range commit.Batch.Requests {
+				o.trace(StageCheckpointed, ack, commit.Batch.SeqNo)
+			}
+		}
+
+		// Anything at or below the checkpoint will never be traced further,
+		// for instance because the node state transferred past it.
+		for seqNo := range o.batches {
+			if seqNo <= t.CEntry.SeqNo {
+				delete(o.batches, seqNo)
+			}
+		}
+		for seqNo := range o.committed {
+			if seqNo <= t.CEntry.SeqNo {
+				delete(o.committed, seqNo)
+			}
+		}
+	}
+}
+
+// Applied emits applied spans for the commits in the given app actions, once
+// the application has applied them.
+func (o *Observer) Applied(actions *statemachine.ActionList) {
+	iter := actions.Iterator()
+	for action := iter.Next(); action != nil; action = iter.Next() {
+		commit, ok := action.Type.(*state.Action_Commit)
+		if !ok {
+			continue
+		}
+
+		for _, ack := range commit.Commit.Batch.Requests {
+			o.trace(StageApplied, ack, commit.Commit.Batch.SeqNo)
+		}
+	}
+}
+
+func (o *Observer) RequestCorrect(ack *msgs.RequestAck) {
+	o.trace(StageCorrect, ack, 0)
+}
+
+func (o *Observer) RequestStrong(ack *msgs.RequestAck) {
+	o.trace(StageStrong, ack, 0)
+}
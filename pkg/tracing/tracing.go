@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package tracing emits spans as requests pass through each stage of the
+// MirBFT pipeline, keyed by client ID, request number, and request digest.
+package tracing
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Stage identifies a point in the pipeline reached by a request.
+type Stage string
+
+const (
+	// StageProposed indicates the request was proposed by a local client.
+	StageProposed Stage = "proposed"
+
+	// StagePersisted indicates the request was persisted to the request store.
+	StagePersisted Stage = "persisted"
+
+	// StageAcked indicates the node sent its ack for the request.
+	StageAcked Stage = "acked"
+
+	// StageCorrect indicates a weak quorum has acked the request.
+	StageCorrect Stage = "correct"
+
+	// StageStrong indicates an intersection quorum has acked the request.
+	StageStrong Stage = "strong"
+
+	// StagePreprepared indicates the node persisted a batch containing the
+	// request at the span's sequence number.
+	StagePreprepared Stage = "preprepared"
+
+	// StagePrepared indicates the batch containing the request prepared.
+	StagePrepared Stage = "prepared"
+
+	// StageCommitted indicates the batch containing the request committed.
+	StageCommitted Stage = "committed"
+
+	// StageApplied indicates the application applied the committed batch.
+	StageApplied Stage = "applied"
+
+	// StageCheckpointed indicates a checkpoint covering the batch was persisted.
+	StageCheckpointed Stage = "checkpointed"
+)
+
+// Span records a request reaching a stage on a particular node.
+type Span struct {
+	NodeID   uint64
+	ClientID uint64
+	ReqNo    uint64
+	Digest   []byte
+	Stage    Stage
+
+	// SeqNo is the sequence number of the batch containing the request,
+	// set for the preprepared stage and beyond.
+	SeqNo uint64
+
+	Time time.Time
+}
+
+// TraceID identifies all spans of a request, across stages and nodes.
+func (s *Span) TraceID() string {
+	return fmt.Sprintf("%d/%d/%x", s.ClientID, s.ReqNo, s.Digest)
+}
+
+// Tracer receives spans.  Implementations must be safe for concurrent use, and
+// must not retain or modify the span's digest.
+type Tracer interface {
+	Trace(span *Span)
+}
+
+// JSONLines is a Tracer writing each span as a single line JSON object.
+type JSONLines struct {
+	mutex  sync.Mutex
+	output io.Writer
+}
+
+// NewJSONLines creates a tracer writing to output.
+func NewJSONLines(output io.Writer) *JSONLines {
+	return &JSONLines{
+		output: output,
+	}
+}
+
+type jsonSpan struct {
+	TraceID  string `json:"trace_id"`
+	NodeID   uint64 `json:"node_id"`
+	ClientID uint64 `json:"client_id"`
+	ReqNo    uint64 `json:"req_no"`
+	Digest   string `json:"digest"`
+	Stage    Stage  `json:"stage"`
+	SeqNo    uint64 `json:"seq_no,omitempty"`
+	Time     string `json:"time"`
+}
+
+func (jl *JSONLines) Trace(span *Span) {
+	data, err := json.Marshal(&jsonSpan{
+		TraceID:  span.TraceID(),
+		NodeID:   span.NodeID,
+		ClientID: span.ClientID,
+		ReqNo:    span.ReqNo,
+		Digest:   hex.EncodeToString(span.Digest),
+		Stage:    span.Stage,
+		SeqNo:    span.SeqNo,
+		Time:     span.Time.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		// all fields are trivially encodable
+		panic(fmt.Sprintf("could not encode span: %s", err))
+	}
+
+	jl.mutex.Lock()
+	defer jl.mutex.Unlock()
+	jl.output.Write(append(data, '\n'))
+}
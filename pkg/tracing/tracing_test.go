@@ -0,0 +1,181 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tracing_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/tracing"
+)
+
+type spanRecorder struct {
+	mutex sync.Mutex
+	spans []*tracing.Span
+}
+
+func (sr *spanRecorder) Trace(span *tracing.Span) {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+	sr.spans = append(sr.spans, span)
+}
+
+func (sr *spanRecorder) stages() []tracing.Stage {
+	result := make([]tracing.Stage, len(sr.spans))
+	for i, span := range sr.spans {
+		result[i] = span.Stage
+	}
+	return result
+}
+
+var _ = Describe("JSONLines", func() {
+	It("writes each span as a line of JSON", func() {
+		output := &bytes.Buffer{}
+		jl := tracing.NewJSONLines(output)
+
+		jl.Trace(&tracing.Span{
+			NodeID:   1,
+			ClientID: 2,
+			ReqNo:    3,
+			Digest:   []byte{0xab, 0xcd},
+			Stage:    tracing.StagePreprepared,
+			SeqNo:    7,
+			Time:     time.Unix(0, 0),
+		})
+		jl.Trace(&tracing.Span{
+			ClientID: 2,
+			ReqNo:    4,
+			Stage:    tracing.StageProposed,
+			Time:     time.Unix(0, 0),
+		})
+
+		Expect(output.String()).To(Equal(
+			`{"trace_id":"2/3/abcd","node_id":1,"client_id":2,"req_no":3,"digest":"abcd","stage":"preprepared","seq_no":7,"time":"1970-01-01T00:00:00Z"}` + "\n" +
+				`{"trace_id":"2/4/","node_id":0,"client_id":2,"req_no":4,"digest":"","stage":"proposed","time":"1970-01-01T00:00:00Z"}` + "\n",
+		))
+
+		decoder := json.NewDecoder(output)
+		for decoder.More() {
+			var span map[string]interface{}
+			Expect(decoder.Decode(&span)).To(Succeed())
+		}
+	})
+})
+
+var _ = Describe("Observer", func() {
+	var (
+		recorder *spanRecorder
+		observer *tracing.Observer
+		ack      *msgs.RequestAck
+	)
+
+	BeforeEach(func() {
+		recorder = &spanRecorder{}
+		observer = tracing.NewObserver(3, recorder)
+		ack = &msgs.RequestAck{
+			ClientId: 1,
+			ReqNo:    2,
+			Digest:   []byte("digest"),
+		}
+	})
+
+	It("derives each stage from the events and actions of the state machine", func() {
+		observer.Proposed((&statemachine.EventList{}).RequestPersisted(ack))
+		observer.Events((&statemachine.EventList{}).RequestPersisted(ack))
+		observer.Actions((&statemachine.ActionList{}).Send(
+			[]uint64{0, 1, 2, 3},
+			&msgs.Msg{
+				Type: &msgs.Msg_RequestAck{
+					RequestAck: ack,
+				},
+			},
+		))
+		observer.RequestCorrect(ack)
+		observer.RequestStrong(ack)
+
+		qEntry := &msgs.QEntry{
+			SeqNo:    6,
+			Digest:   []byte("batch"),
+			Requests: []*msgs.RequestAck{ack},
+		}
+		observer.Actions((&statemachine.ActionList{}).Persist(
+			1,
+			&msgs.Persistent{
+				Type: &msgs.Persistent_QEntry{QEntry: qEntry},
+			},
+		))
+		observer.Actions((&statemachine.ActionList{}).Persist(
+			2,
+			&msgs.Persistent{
+				Type: &msgs.Persistent_PEntry{PEntry: &msgs.PEntry{SeqNo: 6, Digest: []byte("batch")}},
+			},
+		))
+
+		commits := (&statemachine.ActionList{}).Commit(qEntry, 0, 10)
+		observer.Actions(commits)
+		observer.Applied(commits)
+
+		observer.Actions((&statemachine.ActionList{}).Persist(
+			3,
+			&msgs.Persistent{
+				Type: &msgs.Persistent_CEntry{CEntry: &msgs.CEntry{SeqNo: 10}},
+			},
+		))
+
+		Expect(recorder.stages()).To(Equal([]tracing.Stage{
+			tracing.StageProposed,
+			tracing.StagePersisted,
+			tracing.StageAcked,
+			tracing.StageCorrect,
+			tracing.StageStrong,
+			tracing.StagePreprepared,
+			tracing.StagePrepared,
+			tracing.StageCommitted,
+			tracing.StageApplied,
+			tracing.StageCheckpointed,
+		}))
+
+		for _, span := range recorder.spans {
+			Expect(span.NodeID).To(Equal(uint64(3)))
+			Expect(span.TraceID()).To(Equal("1/2/646967657374"))
+		}
+		Expect(recorder.spans[4].SeqNo).To(BeZero())
+		Expect(recorder.spans[9].SeqNo).To(Equal(uint64(6)))
+	})
+
+	It("does not trace batches discarded by a later checkpoint", func() {
+		observer.Actions((&statemachine.ActionList{}).Persist(
+			1,
+			&msgs.Persistent{
+				Type: &msgs.Persistent_QEntry{QEntry: &msgs.QEntry{SeqNo: 6, Requests: []*msgs.RequestAck{ack}}},
+			},
+		))
+		observer.Actions((&statemachine.ActionList{}).Persist(
+			2,
+			&msgs.Persistent{
+				Type: &msgs.Persistent_CEntry{CEntry: &msgs.CEntry{SeqNo: 10}},
+			},
+		))
+		observer.Actions((&statemachine.ActionList{}).Persist(
+			3,
+			&msgs.Persistent{
+				Type: &msgs.Persistent_PEntry{PEntry: &msgs.PEntry{SeqNo: 6}},
+			},
+		))
+
+		Expect(recorder.stages()).To(Equal([]tracing.Stage{
+			tracing.StagePreprepared,
+		}))
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+// Stop gracefully stops the node.  The node immediately stops accepting
+// proposals, steps, and ticks, returning ErrStopping for them, then completes
+// all queued and in-flight work, including WAL writes and syncs, app commits,
+// and checkpoints, before exiting and returning the final status of the state
+// machine.  If the context ends before the node finishes draining, the node
+// is stopped without waiting, and the context's error is returned.  A manually
+// driven node finishes draining once Ready is invoked with no work outstanding,
+// at which point Ready returns ErrStopped.
+func (n *Node) Stop(ctx context.Context) (*status.StateMachine, error) {
+	n.stopOnce.Do(func() {
+		close(n.stopC)
+	})
+	n.manualInbox.wake()
+
+	select {
+	case <-n.workErrNotifier.ExitC():
+	case <-ctx.Done():
+		n.workErrNotifier.Fail(ErrStopped)
+		return nil, errors.WithMessage(ctx.Err(), "node did not finish draining")
+	}
+
+	s, statusErr := n.finalStatus(ctx)
+	if err := n.workErrNotifier.Err(); err != ErrStopped {
+		return s, errors.WithMessage(err, "node failed before it finished draining")
+	}
+
+	return s, statusErr
+}
+
+// finalStatus returns the status of the node after it has exited.
+func (n *Node) finalStatus(ctx context.Context) (*status.StateMachine, error) {
+	if ok, s, err := n.manualStatus(); ok {
+		return s, err
+	}
+
+	select {
+	case <-n.workErrNotifier.ExitStatusC():
+		return n.workErrNotifier.ExitStatus()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (n *Node) stopping() bool {
+	select {
+	case <-n.stopC:
+		return true
+	default:
+		return false
+	}
+}
+
+// finished notifies the processing loop that a worker has completed a batch
+// of work and returned any results, so that the node may determine when it
+// has drained.
+func (n *Node) finished(exitC <-chan struct{}) error {
+	select {
+	case n.workDoneC <- struct{}{}:
+		return nil
+	case <-exitC:
+		return ErrStopped
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft_test
+
+import (
+	"context"
+	"crypto"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger-labs/mirbft"
+	"github.com/hyperledger-labs/mirbft/pkg/pb/msgs"
+	"github.com/hyperledger-labs/mirbft/pkg/reqstore"
+	"github.com/hyperledger-labs/mirbft/pkg/simplewal"
+)
+
+var _ = Describe("Stop", func() {
+	var (
+		tmpDir   string
+		wal      *simplewal.WAL
+		reqStore *reqstore.Store
+		app      *FakeApp
+		node     *mirbft.Node
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "stop_test.*")
+		Expect(err).NotTo(HaveOccurred())
+
+		walPath := filepath.Join(tmpDir, "wal")
+		Expect(os.MkdirAll(walPath, 0700)).To(Succeed())
+		wal, err = simplewal.Open(walPath)
+		Expect(err).NotTo(HaveOccurred())
+
+		reqStorePath := filepath.Join(tmpDir, "reqstore")
+		Expect(os.MkdirAll(reqStorePath, 0700)).To(Succeed())
+		reqStore, err = reqstore.Open(reqStorePath)
+		Expect(err).NotTo(HaveOccurred())
+
+		app = &FakeApp{
+			CommitC: make(chan *msgs.QEntry, 100),
+		}
+
+		node, err = mirbft.NewNode(
+			0,
+			&mirbft.Config{
+				BatchSize:            1,
+				SuspectTicks:         4,
+				HeartbeatTicks:       2,
+				NewEpochTimeoutTicks: 8,
+				BufferSize:           5 * 1024 * 1024,
+				Logger:               mirbft.ConsoleWarnLogger,
+			},
+			&mirbft.ProcessorConfig{
+				Link:         NewFakeTransport(1).Link(0),
+				Hasher:       crypto.SHA256,
+				RequestStore: reqStore,
+				App:          app,
+				WAL:          wal,
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		wal.Close()
+		reqStore.Close()
+		os.RemoveAll(tmpDir)
+	})
+
+	It("drains the work of a processing node before exiting", func() {
+		exitC := make(chan struct{})
+		defer close(exitC)
+		ticker := time.NewTicker(10 * time.Millisecond)
+		defer ticker.Stop()
+
+		errC := make(chan error, 1)
+		go func() {
+			errC <- node.ProcessAsNewNode(exitC, ticker.C, mirbft.StandardInitialNetworkState(1, 1), []byte("fake"))
+		}()
+
+		client := node.Client(0)
+		Eventually(func() error {
+			_, err := client.NextReqNo()
+			return err
+		}).Should(Succeed())
+
+		_, err := client.ProposeAndWait(context.Background(), 0, clientReq(0, 0))
+		Expect(err).NotTo(HaveOccurred())
+		for i := uint64(1); i < 10; i++ {
+			Expect(client.Propose(context.Background(), i, clientReq(0, i))).To(Succeed())
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		s, err := node.Stop(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).NotTo(BeNil())
+		Eventually(errC).Should(Receive(Equal(mirbft.ErrStopped)))
+
+		// Every commit issued to the app was applied before exiting.
+		Expect(s.CommitState.CheckpointPending).To(BeFalse())
+		Expect(app.Entries).NotTo(BeEmpty())
+		Expect(app.Entries[len(app.Entries)-1].SeqNo).To(Equal(s.CommitState.LastAppliedCommit))
+
+		Expect(client.Propose(context.Background(), 10, clientReq(0, 10))).To(Equal(mirbft.ErrStopping))
+		Expect(node.Step(context.Background(), 0, &msgs.Msg{})).To(Equal(mirbft.ErrStopping))
+
+		s, err = node.Stop(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).NotTo(BeNil())
+	})
+
+	It("drains the work of a manually driven node before exiting", func() {
+		Expect(node.InitializeAsNewNode(mirbft.StandardInitialNetworkState(1, 1), []byte("fake"))).To(Succeed())
+
+		exitC := make(chan struct{})
+		defer close(exitC)
+		errC := make(chan error, 1)
+		go func() {
+			errC <- (&mirbft.SerialProcessor{Node: node}).Run(exitC, nil)
+		}()
+
+		client := node.Client(0)
+		Eventually(func() error {
+			_, err := client.NextReqNo()
+			return err
+		}).Should(Succeed())
+
+		for i := uint64(0); i < 5; i++ {
+			Expect(client.Propose(context.Background(), i, clientReq(0, i))).To(Succeed())
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		s, err := node.Stop(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).NotTo(BeNil())
+		Eventually(errC).Should(Receive(Equal(mirbft.ErrStopped)))
+
+		Expect(s.CommitState.CheckpointPending).To(BeFalse())
+		Expect(node.Tick()).To(Equal(mirbft.ErrStopping))
+		Expect(client.Propose(context.Background(), 5, clientReq(0, 5))).To(Equal(mirbft.ErrStopping))
+	})
+})
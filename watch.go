@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mirbft
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hyperledger-labs/mirbft/pkg/statemachine"
+	"github.com/hyperledger-labs/mirbft/pkg/status"
+)
+
+// StatusFilter selects the facets of the status which trigger a new snapshot
+// for WatchStatus.
+type StatusFilter int
+
+const (
+	// WatchEpoch triggers on a change to the epoch number or epoch state.
+	WatchEpoch StatusFilter = 1 << iota
+
+	// WatchWatermarks triggers on a change to the low or high watermark.
+	WatchWatermarks
+
+	// WatchCheckpoints triggers when a new checkpoint becomes stable.
+	WatchCheckpoints
+
+	// WatchAll triggers on a change to any watched facet.
+	WatchAll = WatchEpoch | WatchWatermarks | WatchCheckpoints
+)
+
+func (f StatusFilter) changed(old, new *status.Facets) bool {
+	switch {
+	case f&WatchEpoch != 0 && (old.EpochNumber != new.EpochNumber || old.EpochState != new.EpochState):
+		return true
+	case f&WatchWatermarks != 0 && (old.LowWatermark != new.LowWatermark || old.HighWatermark != new.HighWatermark):
+		return true
+	case f&WatchCheckpoints != 0 && old.StableCheckpoint != new.StableCheckpoint:
+		return true
+	default:
+		return false
+	}
+}
+
+type statusWatcher struct {
+	filter StatusFilter
+	last   *status.Facets
+	c      chan *status.StateMachine
+}
+
+// statusWatchers tracks the outstanding WatchStatus calls.  It is notified
+// by the go routine applying events to the state machine after each batch.
+type statusWatchers struct {
+	mutex    sync.Mutex
+	watchers map[*statusWatcher]struct{}
+}
+
+func newStatusWatchers() *statusWatchers {
+	return &statusWatchers{
+		watchers: map[*statusWatcher]struct{}{},
+	}
+}
+
+func (sw *statusWatchers) add(filter StatusFilter) *statusWatcher {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	w := &statusWatcher{
+		filter: filter,
+		c:      make(chan *status.StateMachine, 1),
+	}
+	sw.watchers[w] = struct{}{}
+	return w
+}
+
+func (sw *statusWatchers) remove(w *statusWatcher) {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	delete(sw.watchers, w)
+	close(w.c)
+}
+
+// notify delivers a snapshot to each watcher whose watched facets have changed
+// since its last snapshot.  A watcher which has not yet consumed its previous
+// snapshot has it replaced, so that a slow consumer sees only the latest status.
+// It must be invoked from the go routine applying events to the state machine.
+func (sw *statusWatchers) notify(sm *statemachine.StateMachine) error {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	if len(sw.watchers) == 0 {
+		return nil
+	}
+
+	facets := sm.Facets()
+	var s *status.StateMachine
+	for w := range sw.watchers {
+		if w.last != nil && !w.filter.changed(w.last, facets) {
+			continue
+		}
+
+		if s == nil {
+			var err error
+			s, err = sm.Status()
+			if err != nil {
+				return err
+			}
+		}
+
+		w.last = facets
+
+		select {
+		case <-w.c:
+		default:
+		}
+		w.c <- s
+	}
+
+	return nil
+}
+
+// WatchStatus returns a channel which receives a status snapshot whenever any
+// facet selected by filter changes.  The first snapshot is delivered once the
+// next batch of events has been applied to the state machine.  Snapshots are
+// coalesced, so a consumer which falls behind receives only the most recent
+// one.  The channel is closed when the context ends or the node stops.  The
+// snapshots are shared between watchers and must not be modified.
+func (n *Node) WatchStatus(ctx context.Context, filter StatusFilter) <-chan *status.StateMachine {
+	w := n.statusWatchers.add(filter)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-n.workErrNotifier.ExitC():
+		}
+		n.statusWatchers.remove(w)
+	}()
+
+	return w.c
+}